@@ -1,185 +1,153 @@
 package main
 
 import (
+	"context"
 	"database/sql"
+	"flag"
 	"fmt"
-	"io/fs"
 	"log"
 	"os"
 	"path/filepath"
-	"sort"
-	"strings"
+	"strconv"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/config"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db/migrate"
 )
 
+const usage = `Usage: migrate [flags] [command]
+
+Commands:
+  up        apply all pending migrations (default)
+  down [N]  revert the N most recently applied migrations (default 1)
+  status    list migrations and whether they are applied
+  redo      revert and reapply the most recently applied migration
+
+Flags:
+`
+
 func main() {
-	dbPath := os.Getenv("DATABASE_URL")
-	if dbPath == "" {
-		dbPath = "./data/conduit.db"
+	flags := flag.NewFlagSet("migrate", flag.ExitOnError)
+	dryRun := flags.Bool("dry-run", false, "print the statements that would run without running them")
+	dir := flags.String("dir", "", "read migrations from this directory instead of the ones built into the binary")
+	flags.Usage = func() {
+		fmt.Fprint(flags.Output(), usage)
+		flags.PrintDefaults()
 	}
 
-	// Create data directory if it doesn't exist
-	if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
-		log.Fatal("Failed to create data directory:", err)
-	}
+	args := parseArgs(flags, os.Args[1:])
 
-	db, err := sql.Open("sqlite3", dbPath)
+	// Only the database settings are needed, so the server's required settings are not checked
+	cfg, err := config.Load()
 	if err != nil {
-		log.Fatal("Failed to open database:", err)
+		log.Fatal("Failed to load configuration:", err)
 	}
-	defer db.Close()
+	dbPath := cfg.DatabaseURL
 
-	// Test database connection
-	if err := db.Ping(); err != nil {
-		log.Fatal("Failed to ping database:", err)
+	// PostgreSQL has its own migrations, SQLite needs its data directory
+	dialect := db.DialectFromURL(dbPath)
+	driverName := "sqlite3"
+	if dialect == db.Postgres {
+		driverName = "postgres"
+	} else if err := os.MkdirAll(filepath.Dir(dbPath), 0755); err != nil {
+		log.Fatal("Failed to create data directory:", err)
 	}
 
-	// Create migrations table if it doesn't exist
-	if err := createMigrationsTable(db); err != nil {
-		log.Fatal("Failed to create migrations table:", err)
+	migrations := migrate.Embedded(dialect)
+	if *dir != "" {
+		migrations = os.DirFS(*dir)
 	}
 
-	// Run migrations
-	if err := runMigrations(db); err != nil {
-		log.Fatal("Failed to run migrations:", err)
+	database, err := sql.Open(driverName, dbPath)
+	if err != nil {
+		log.Fatal("Failed to open database:", err)
 	}
+	defer database.Close()
 
-	fmt.Println("Database migrations completed successfully!")
-}
-
-func createMigrationsTable(db *sql.DB) error {
-	query := `
-	CREATE TABLE IF NOT EXISTS schema_migrations (
-		version TEXT PRIMARY KEY,
-		applied_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	_, err := db.Exec(query)
-	return err
-}
-
-func runMigrations(db *sql.DB) error {
-	migrationsDir := "./migrations"
-
-	// Get applied migrations
-	appliedMigrations, err := getAppliedMigrations(db)
-	if err != nil {
-		return fmt.Errorf("failed to get applied migrations: %w", err)
+	// Test database connection
+	if err := database.Ping(); err != nil {
+		log.Fatal("Failed to ping database:", err)
 	}
 
-	// Read migration files
-	migrationFiles, err := getMigrationFiles(migrationsDir)
+	m, err := migrate.New(database, dialect, migrations)
 	if err != nil {
-		return fmt.Errorf("failed to read migration files: %w", err)
+		log.Fatal(err)
 	}
+	m.DryRun = *dryRun
+	m.Out = os.Stdout
 
-	// Apply new migrations
-	for _, file := range migrationFiles {
-		version := getVersionFromFilename(file)
-
-		if _, applied := appliedMigrations[version]; applied {
-			fmt.Printf("Migration %s already applied, skipping\n", version)
-			continue
-		}
-
-		fmt.Printf("Applying migration %s\n", version)
-
-		if err := applyMigration(db, migrationsDir, file, version); err != nil {
-			return fmt.Errorf("failed to apply migration %s: %w", version, err)
-		}
+	if err := runCommand(m, args); err != nil {
+		log.Fatal(err)
 	}
-
-	return nil
 }
 
-func getAppliedMigrations(db *sql.DB) (map[string]bool, error) {
-	rows, err := db.Query("SELECT version FROM schema_migrations")
-	if err != nil {
-		return nil, err
-	}
-	defer rows.Close()
-
-	applied := make(map[string]bool)
-	for rows.Next() {
-		var version string
-		if err := rows.Scan(&version); err != nil {
-			return nil, err
+// parseArgs parses flags placed before, between or after the positional
+// arguments and returns the positional arguments
+func parseArgs(flags *flag.FlagSet, args []string) []string {
+	var positional []string
+	for {
+		// ExitOnError makes Parse exit on invalid flags
+		_ = flags.Parse(args)
+		if flags.NArg() == 0 {
+			return positional
 		}
-		applied[version] = true
+		positional = append(positional, flags.Arg(0))
+		args = flags.Args()[1:]
 	}
-
-	return applied, rows.Err()
 }
 
-func getMigrationFiles(dir string) ([]string, error) {
-	var files []string
+// runCommand runs the command named by args[0] with the remaining arguments.
+// Commands that change the schema hold the migration lock, so they do not race
+// a server migrating on start.
+func runCommand(m *migrate.Migrator, args []string) error {
+	command := "up"
+	if len(args) > 0 {
+		command, args = args[0], args[1:]
+	}
 
-	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
-		if err != nil {
+	switch {
+	case command == "up" && len(args) == 0:
+		if err := withLock(m, func() error {
+			_, err := m.Up(0)
 			return err
+		}); err != nil {
+			return fmt.Errorf("failed to run migrations: %w", err)
 		}
-
-		if !d.IsDir() && strings.HasSuffix(path, ".sql") {
-			files = append(files, d.Name())
+		if !m.DryRun {
+			fmt.Fprintln(m.Out, "Database migrations completed successfully!")
 		}
-
 		return nil
-	})
-
-	if err != nil {
-		return nil, err
-	}
-
-	sort.Strings(files)
-	return files, nil
-}
-
-func getVersionFromFilename(filename string) string {
-	// Extract version from filename like "001_initial_schema.sql"
-	parts := strings.Split(filename, "_")
-	if len(parts) > 0 {
-		return parts[0]
-	}
-	return filename
-}
-
-func applyMigration(db *sql.DB, dir, filename, version string) error {
-	filePath := filepath.Join(dir, filename)
-	content, err := os.ReadFile(filePath)
-	if err != nil {
-		return err
-	}
-
-	// Execute migration
-	tx, err := db.Begin()
-	if err != nil {
-		return err
-	}
-	defer func() {
-		if err := tx.Rollback(); err != nil {
-			log.Printf("Failed to rollback transaction: %v", err)
+	case command == "down" && len(args) <= 1:
+		n := 1
+		if len(args) == 1 {
+			var err error
+			if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+				return fmt.Errorf("invalid number of migrations to revert: %q", args[0])
+			}
 		}
-	}()
-
-	// Split by semicolon and execute each statement
-	statements := strings.Split(string(content), ";")
-	for _, stmt := range statements {
-		stmt = strings.TrimSpace(stmt)
-		if stmt == "" {
-			continue
+		if err := withLock(m, func() error { return m.Down(n) }); err != nil {
+			return fmt.Errorf("failed to revert migrations: %w", err)
 		}
-
-		if _, err := tx.Exec(stmt); err != nil {
-			return fmt.Errorf("failed to execute statement: %s, error: %w", stmt, err)
+		return nil
+	case command == "status" && len(args) == 0:
+		return m.Status()
+	case command == "redo" && len(args) == 0:
+		if err := withLock(m, m.Redo); err != nil {
+			return fmt.Errorf("failed to redo migration: %w", err)
 		}
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run migrate -h for usage", command)
 	}
+}
 
-	// Record migration as applied
-	_, err = tx.Exec("INSERT INTO schema_migrations (version) VALUES (?)", version)
-	if err != nil {
-		return err
+// withLock runs fn under the migration lock unless this is a dry run, which
+// changes nothing and should not wait for a migration in progress
+func withLock(m *migrate.Migrator, fn func() error) error {
+	if m.DryRun {
+		return fn()
 	}
-
-	return tx.Commit()
+	return m.WithLock(context.Background(), fn)
 }
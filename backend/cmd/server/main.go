@@ -1,106 +1,208 @@
 package main
 
 import (
+	"context"
 	"encoding/json"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
+	"net"
 	"net/http"
 	"os"
-	"strings"
+	"os/signal"
+	"syscall"
+	"time"
 
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/config"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db/memory"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db/migrate"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/handlers"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/logging"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/tracing"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/utils"
 )
 
 var database *db.DB
 
+// cfg is the server configuration, the defaults until main has loaded it
+var cfg = config.Default()
+
+// inMemory is set when the server runs with -storage=memory and has no database to check
+var inMemory bool
+
 func main() {
-	port := os.Getenv("PORT")
-	if port == "" {
-		port = "8080"
-	}
+	storage := flag.String("storage", "database", "storage backend: database (DATABASE_URL) or memory")
+	flag.Parse()
+
+	// Log JSON from the start, at the configured level once it is known
+	slog.SetDefault(logging.New(os.Stdout, slog.LevelInfo))
 
-	// Initialize database connection
 	var err error
-	database, err = db.NewConnection()
+	cfg, err = config.Load()
 	if err != nil {
-		log.Fatalf("Failed to connect to database: %v", err)
+		fatal("Failed to load configuration", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		fatal("Invalid configuration", err)
 	}
-	defer database.Close()
 
-	// Initialize repositories
-	userRepo := db.NewUserRepository(database.DB)
-	articleRepo := db.NewArticleRepository(database.DB)
-	commentRepo := db.NewCommentRepository(database.DB)
+	logger := logging.New(os.Stdout, cfg.Level())
+	slog.SetDefault(logger)
 
-	// Initialize handlers
-	userHandler := handlers.NewUserHandler(userRepo)
-	articleHandler := handlers.NewArticleHandler(articleRepo, userRepo)
-	commentHandler := handlers.NewCommentHandler(commentRepo, userRepo)
-
-	mux := http.NewServeMux()
-
-	// Health check endpoint
-	mux.HandleFunc("/health", healthCheckHandler)
-
-	// User API routes
-	mux.HandleFunc("/api/users", userHandler.Register)
-	mux.HandleFunc("/api/users/login", userHandler.Login)
-	mux.HandleFunc("/api/user", handlers.AuthMiddleware(userHandler.GetCurrentUser))
-
-	// Article API routes
-	mux.HandleFunc("/api/articles", func(w http.ResponseWriter, r *http.Request) {
-		if r.Method == http.MethodGet {
-			articleHandler.GetArticles(w, r)
-		} else if r.Method == http.MethodPost {
-			handlers.AuthMiddleware(articleHandler.CreateArticle)(w, r)
-		} else {
-			handlers.WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
+	shutdownTracing, err := tracing.Setup(context.Background(), cfg.TracingOptions())
+	if err != nil {
+		fatal("Failed to set up tracing", err)
+	}
+
+	utils.SetSlugOptions(cfg.SlugOptions())
+	db.SetQueryTimeout(cfg.DBQueryTimeout)
+	tokens := auth.NewTokenManager(cfg.JWTSecret)
+
+	var s stores
+	switch *storage {
+	case "database":
+		// Initialize database connection, closed after the server has shut down
+		database, err = db.NewConnection(cfg.DatabaseURL)
+		if err != nil {
+			fatal("Failed to connect to database", err)
 		}
-	})
 
-	// Article CRUD routes
-	mux.HandleFunc("/api/articles/", func(w http.ResponseWriter, r *http.Request) {
-		path := strings.TrimPrefix(r.URL.Path, "/api/articles/")
-		parts := strings.Split(path, "/")
-
-		if len(parts) == 1 && parts[0] != "" {
-			// /api/articles/{slug}
-			if r.Method == http.MethodGet {
-				articleHandler.GetArticle(w, r)
-			} else if r.Method == http.MethodPut {
-				handlers.AuthMiddleware(articleHandler.UpdateArticle)(w, r)
-			} else if r.Method == http.MethodDelete {
-				handlers.AuthMiddleware(articleHandler.DeleteArticle)(w, r)
-			} else {
-				handlers.WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-			}
-		} else if len(parts) == 2 && parts[1] == "comments" {
-			// /api/articles/{slug}/comments
-			if r.Method == http.MethodGet {
-				commentHandler.GetComments(w, r)
-			} else if r.Method == http.MethodPost {
-				handlers.AuthMiddleware(commentHandler.CreateComment)(w, r)
-			} else {
-				handlers.WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
+		if cfg.AutoMigrate {
+			if err := autoMigrate(database); err != nil {
+				fatal("Failed to migrate database", err)
 			}
-		} else if len(parts) == 3 && parts[1] == "comments" {
-			// /api/articles/{slug}/comments/{id}
-			if r.Method == http.MethodDelete {
-				handlers.AuthMiddleware(commentHandler.DeleteComment)(w, r)
-			} else {
-				handlers.WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-			}
-		} else {
-			handlers.WriteErrorResponse(w, http.StatusNotFound, "path", "Endpoint not found")
 		}
-	})
 
-	// Default API route
-	mux.HandleFunc("/api/", apiHandler)
+		if err := metrics.RegisterDB(database.DB, "conduit"); err != nil {
+			fatal("Failed to register database metrics", err)
+		}
+
+		s = databaseStores(database)
+	case "memory":
+		slog.Warn("Using in-memory storage, data is lost on restart")
+		inMemory = true
+		s = memoryStores(memory.New())
+	default:
+		fatal("Invalid -storage flag", fmt.Errorf("unknown storage %q, expected database or memory", *storage))
+	}
+
+	// Remove tags left behind by articles deleted before orphan cleanup existed
+	if removed, err := s.tags.DeleteOrphans(context.Background()); err != nil {
+		slog.Error("Failed to clean up orphan tags", "error", err)
+	} else if removed > 0 {
+		slog.Info("Removed orphan tags", "count", removed)
+	}
+
+	// Initialize handlers
+	userHandler := handlers.NewUserHandler(s.users, tokens)
+	articleHandler := handlers.NewArticleHandler(s.articles, s.users)
+	commentHandler := handlers.NewCommentHandler(s.comments, s.users)
+	profileHandler := handlers.NewProfileHandler(s.users, s.follows)
+	tagHandler := handlers.NewTagHandler(s.tags)
+
+	router := newRouter(apiHandlers{
+		user:    userHandler,
+		profile: profileHandler,
+		article: articleHandler,
+		comment: commentHandler,
+		tag:     tagHandler,
+		tokens:  tokens,
+	}, logger)
+
+	// ECS sends SIGTERM before stopping the task, Ctrl+C sends SIGINT
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	srv := newHTTPServer(":"+cfg.Port, router, cfg)
+	ln, err := net.Listen("tcp", srv.Addr)
+	if err != nil {
+		fatal("Failed to listen", err)
+	}
+
+	slog.Info("RealWorld Conduit API server starting", "port", cfg.Port, "environment", cfg.Environment)
+	serveErr := serve(ctx, srv, ln, cfg.ShutdownTimeout)
+	if serveErr != nil {
+		slog.Error("Server stopped", "error", serveErr)
+	} else {
+		slog.Info("Server shut down gracefully")
+	}
+
+	// Export the spans of the last requests
+	flushCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("Failed to flush traces", "error", err)
+	}
+	cancel()
+
+	if database != nil {
+		if err := database.Close(); err != nil {
+			slog.Error("Failed to close database", "error", err)
+		}
+	}
+
+	if serveErr != nil {
+		os.Exit(1)
+	}
+}
 
-	fmt.Printf("🚀 RealWorld Conduit API server starting on port %s - API Gateway Integration Ready\n", port)
-	log.Fatal(http.ListenAndServe(":"+port, handlers.CORSMiddleware(mux)))
+// fatal logs an error that prevents the server from starting and exits
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
+
+// stores groups the storage backends the handlers depend on
+type stores struct {
+	users    db.UserStore
+	articles db.ArticleStore
+	comments db.CommentStore
+	follows  db.FollowStore
+	tags     db.TagStore
+}
+
+// databaseStores returns stores backed by the SQL repositories
+func databaseStores(database *db.DB) stores {
+	return stores{
+		users:    db.NewUserRepository(database.DB),
+		articles: db.NewArticleRepository(database.DB),
+		comments: db.NewCommentRepository(database.DB),
+		follows:  db.NewFollowRepository(database.DB),
+		tags:     db.NewTagRepository(database.DB),
+	}
+}
+
+// memoryStores returns stores backed by a single in-memory store
+func memoryStores(m *memory.Store) stores {
+	return stores{
+		users:    m.Users(),
+		articles: m.Articles(),
+		comments: m.Comments(),
+		follows:  m.Follows(),
+		tags:     m.Tags(),
+	}
+}
+
+// autoMigrate applies the embedded migrations before the server starts serving.
+// The migration lock makes replicas starting together wait for the first one.
+func autoMigrate(database *db.DB) error {
+	m, err := migrate.New(database.DB, database.Dialect, migrate.Embedded(database.Dialect))
+	if err != nil {
+		return err
+	}
+	// Each line printed by the migrator becomes a JSON log record
+	m.Out = slog.NewLogLogger(slog.Default().Handler(), slog.LevelInfo).Writer()
+
+	return m.WithLock(context.Background(), func() error {
+		applied, err := m.Up(0)
+		if err != nil {
+			return err
+		}
+		slog.Info("Applied migrations", "count", applied)
+		return nil
+	})
 }
 
 func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
@@ -111,8 +213,8 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 		"status":    "ok",
 		"service":   "conduit-api", 
 		"version":   "1.0.0",
-		"timestamp": os.Getenv("BUILD_TIMESTAMP"),
-		"environment": os.Getenv("ENVIRONMENT"),
+		"timestamp": cfg.BuildTimestamp,
+		"environment": cfg.Environment,
 	}
 	
 	// Check database connectivity
@@ -125,6 +227,8 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 		} else {
 			response["database"] = "connected"
 		}
+	} else if inMemory {
+		response["database"] = "memory"
 	} else {
 		response["status"] = "unhealthy"
 		response["database"] = "not_initialized"
@@ -138,26 +242,14 @@ func healthCheckHandler(w http.ResponseWriter, r *http.Request) {
 	
 	jsonResponse, _ := json.Marshal(response)
 	if _, err := w.Write(jsonResponse); err != nil {
-		log.Printf("Failed to write health check response: %v", err)
+		logging.FromContext(r.Context()).Error("Failed to write health check response", "error", err)
 	}
 }
 
 func apiHandler(w http.ResponseWriter, r *http.Request) {
-	// Handle different endpoints based on the path
-	path := strings.TrimPrefix(r.URL.Path, "/api")
-
-	switch {
-	case path == "/" || path == "":
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusOK)
-		if _, err := w.Write([]byte(`{"message": "Conduit API - RealWorld implementation"}`)); err != nil {
-			log.Printf("Failed to write API response: %v", err)
-		}
-	default:
-		w.Header().Set("Content-Type", "application/json")
-		w.WriteHeader(http.StatusNotFound)
-		if _, err := w.Write([]byte(`{"errors": {"path": ["Endpoint not found"]}}`)); err != nil {
-			log.Printf("Failed to write error response: %v", err)
-		}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(http.StatusOK)
+	if _, err := w.Write([]byte(`{"message": "Conduit API - RealWorld implementation"}`)); err != nil {
+		logging.FromContext(r.Context()).Error("Failed to write API response", "error", err)
 	}
 }
@@ -2,12 +2,16 @@ package main
 
 import (
 	"encoding/json"
+	"io"
+	"log/slog"
 	"net/http"
 	"net/http/httptest"
-	"os"
 	"testing"
 
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/handlers"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/logging"
 )
 
 func TestHealthCheckHandler(t *testing.T) {
@@ -36,12 +40,8 @@ func TestHealthCheckHandler(t *testing.T) {
 			// Setup
 			if tt.setupDatabase {
 				// Use in-memory database for testing
-				originalDBPath := os.Getenv("DATABASE_URL")
-				os.Setenv("DATABASE_URL", ":memory:")
-				defer os.Setenv("DATABASE_URL", originalDBPath)
-				
 				var err error
-				database, err = db.NewConnection()
+				database, err = db.NewConnection(":memory:")
 				if err != nil {
 					t.Fatalf("Failed to setup test database: %v", err)
 				}
@@ -106,13 +106,9 @@ func TestHealthCheckHandler(t *testing.T) {
 
 func TestHealthCheckHandlerDatabaseConnected(t *testing.T) {
 	// Use in-memory database for testing
-	originalDBPath := os.Getenv("DATABASE_URL")
-	os.Setenv("DATABASE_URL", ":memory:")
-	defer os.Setenv("DATABASE_URL", originalDBPath)
-	
 	// Setup database
 	var err error
-	database, err = db.NewConnection()
+	database, err = db.NewConnection(":memory:")
 	if err != nil {
 		t.Fatalf("Failed to setup test database: %v", err)
 	}
@@ -151,4 +147,67 @@ func TestHealthCheckHandlerDatabaseConnected(t *testing.T) {
 	if dbStatus, ok := response["database"].(string); !ok || dbStatus != "connected" {
 		t.Errorf("Expected database status to be 'connected', got %v", response["database"])
 	}
-}
\ No newline at end of file
+}
+
+func TestRouter(t *testing.T) {
+	testDB, err := db.NewConnection(":memory:")
+	if err != nil {
+		t.Fatalf("Failed to setup test database: %v", err)
+	}
+	defer testDB.Close()
+
+	tokens := auth.NewTokenManager("test-secret-key")
+	userRepo := db.NewUserRepository(testDB.DB)
+	articleRepo := db.NewArticleRepository(testDB.DB)
+	router := newRouter(apiHandlers{
+		user:    handlers.NewUserHandler(userRepo, tokens),
+		profile: handlers.NewProfileHandler(userRepo, db.NewFollowRepository(testDB.DB)),
+		article: handlers.NewArticleHandler(articleRepo, userRepo),
+		comment: handlers.NewCommentHandler(db.NewCommentRepository(testDB.DB), userRepo),
+		tag:     handlers.NewTagHandler(db.NewTagRepository(testDB.DB)),
+		tokens:  tokens,
+	}, logging.New(io.Discard, slog.LevelInfo))
+
+	tests := []struct {
+		name           string
+		method         string
+		path           string
+		expectedStatus int
+		expectedField  string
+		expectedAllow  string
+	}{
+		{"feed is not treated as a slug", http.MethodGet, "/api/articles/feed", http.StatusUnauthorized, "token", ""},
+		{"method not allowed on article", http.MethodPatch, "/api/articles/some-slug", http.StatusMethodNotAllowed, "method", "DELETE, GET, HEAD, PUT"},
+		{"method not allowed on favorite", http.MethodGet, "/api/articles/some-slug/favorite", http.StatusMethodNotAllowed, "method", "DELETE, POST"},
+		{"unknown endpoint", http.MethodGet, "/api/unknown", http.StatusNotFound, "path", ""},
+		{"too many segments", http.MethodGet, "/api/articles/a/comments/b/c", http.StatusNotFound, "path", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(tt.method, tt.path, nil)
+			rr := httptest.NewRecorder()
+			router.ServeHTTP(rr, req)
+
+			if rr.Code != tt.expectedStatus {
+				t.Fatalf("Expected status code %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			if contentType := rr.Header().Get("Content-Type"); contentType != "application/json" {
+				t.Errorf("Expected JSON content type, got %q", contentType)
+			}
+
+			var response handlers.ErrorResponse
+			if err := json.Unmarshal(rr.Body.Bytes(), &response); err != nil {
+				t.Fatalf("Failed to parse response JSON %q: %v", rr.Body.String(), err)
+			}
+			if _, ok := response.Errors[tt.expectedField]; !ok {
+				t.Errorf("Expected error field %q, got %v", tt.expectedField, response.Errors)
+			}
+
+			if allow := rr.Header().Get("Allow"); allow != tt.expectedAllow {
+				t.Errorf("Expected Allow header %q, got %q", tt.expectedAllow, allow)
+			}
+		})
+	}
+}
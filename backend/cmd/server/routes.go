@@ -0,0 +1,78 @@
+package main
+
+import (
+	"log/slog"
+	"net/http"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/handlers"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
+)
+
+// apiHandlers groups the HTTP handlers served by the router
+type apiHandlers struct {
+	user    *handlers.UserHandler
+	profile *handlers.ProfileHandler
+	article *handlers.ArticleHandler
+	comment *handlers.CommentHandler
+	tag     *handlers.TagHandler
+	// tokens validates the tokens of authenticated routes
+	tokens *auth.TokenManager
+}
+
+// newRouter registers all API routes using method and path patterns.
+// Literal segments such as /api/articles/feed take precedence over {slug}
+// wildcards, and unmatched methods get a 405 with an Allow header.
+func newRouter(h apiHandlers, logger *slog.Logger) http.Handler {
+	mux := http.NewServeMux()
+
+	// Health check endpoint
+	mux.HandleFunc("GET /health", healthCheckHandler)
+
+	// Prometheus metrics
+	mux.Handle("GET /metrics", metrics.Handler())
+
+	// User API routes
+	mux.HandleFunc("POST /api/users", h.user.Register)
+	mux.HandleFunc("POST /api/users/login", h.user.Login)
+	mux.HandleFunc("GET /api/user", handlers.AuthMiddleware(h.tokens, h.user.GetCurrentUser))
+	mux.HandleFunc("PUT /api/user", handlers.AuthMiddleware(h.tokens, h.user.UpdateCurrentUser))
+
+	// Profile API routes
+	mux.HandleFunc("GET /api/profiles/{username}", handlers.OptionalAuthMiddleware(h.tokens, h.profile.GetProfile))
+	mux.HandleFunc("POST /api/profiles/{username}/follow", handlers.AuthMiddleware(h.tokens, h.profile.FollowUser))
+	mux.HandleFunc("DELETE /api/profiles/{username}/follow", handlers.AuthMiddleware(h.tokens, h.profile.UnfollowUser))
+
+	// Article API routes
+	mux.HandleFunc("GET /api/articles", handlers.OptionalAuthMiddleware(h.tokens, h.article.GetArticles))
+	mux.HandleFunc("POST /api/articles", handlers.AuthMiddleware(h.tokens, h.article.CreateArticle))
+	mux.HandleFunc("GET /api/articles/feed", handlers.AuthMiddleware(h.tokens, h.article.GetFeed))
+	mux.HandleFunc("GET /api/articles/{slug}", handlers.OptionalAuthMiddleware(h.tokens, h.article.GetArticle))
+	mux.HandleFunc("PUT /api/articles/{slug}", handlers.AuthMiddleware(h.tokens, h.article.UpdateArticle))
+	mux.HandleFunc("DELETE /api/articles/{slug}", handlers.AuthMiddleware(h.tokens, h.article.DeleteArticle))
+	mux.HandleFunc("POST /api/articles/{slug}/favorite", handlers.AuthMiddleware(h.tokens, h.article.FavoriteArticle))
+	mux.HandleFunc("DELETE /api/articles/{slug}/favorite", handlers.AuthMiddleware(h.tokens, h.article.UnfavoriteArticle))
+
+	// Comment API routes
+	mux.HandleFunc("GET /api/articles/{slug}/comments", handlers.OptionalAuthMiddleware(h.tokens, h.comment.GetComments))
+	mux.HandleFunc("POST /api/articles/{slug}/comments", handlers.AuthMiddleware(h.tokens, h.comment.CreateComment))
+	mux.HandleFunc("DELETE /api/articles/{slug}/comments/{id}", handlers.AuthMiddleware(h.tokens, h.comment.DeleteComment))
+
+	// Tag API routes
+	mux.HandleFunc("GET /api/tags", h.tag.GetTags)
+
+	// API root
+	mux.HandleFunc("GET /api/{$}", apiHandler)
+
+	// Middleware is applied from the innermost out
+	var handler http.Handler = handlers.RouteErrorMiddleware(mux)
+	handler = handlers.StripIdentityHeadersMiddleware(handler)
+	handler = handlers.CORSMiddleware(handler)
+	handler = handlers.MetricsMiddleware(mux, handler)
+	// Recovery runs inside request logging so that panics are logged with the
+	// request ID and the access log records the 500
+	handler = handlers.RecoveryMiddleware(handler)
+	handler = handlers.RequestLoggingMiddleware(logger, handler)
+	// Tracing runs first so that log records carry the trace ID
+	return handlers.TracingMiddleware(mux, handler)
+}
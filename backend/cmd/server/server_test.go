@@ -0,0 +1,92 @@
+package main
+
+import (
+	"context"
+	"io"
+	"net"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/config"
+)
+
+func TestServe_DrainsInFlightRequests(t *testing.T) {
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		time.Sleep(100 * time.Millisecond)
+		w.Write([]byte("done"))
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, newHTTPServer(ln.Addr().String(), handler, config.Default()), ln, time.Second)
+	}()
+
+	type result struct {
+		body string
+		err  error
+	}
+	responses := make(chan result, 1)
+	go func() {
+		resp, err := http.Get("http://" + ln.Addr().String())
+		if err != nil {
+			responses <- result{err: err}
+			return
+		}
+		defer resp.Body.Close()
+		body, err := io.ReadAll(resp.Body)
+		responses <- result{body: string(body), err: err}
+	}()
+
+	// Shut down while the request is being handled
+	<-started
+	cancel()
+
+	if r := <-responses; r.err != nil || r.body != "done" {
+		t.Errorf("Expected the in-flight request to complete, got %q, %v", r.body, r.err)
+	}
+	if err := <-served; err != nil {
+		t.Errorf("Expected a graceful shutdown, got %v", err)
+	}
+
+	if _, err := http.Get("http://" + ln.Addr().String()); err == nil {
+		t.Error("Expected new connections to be refused after shutdown")
+	}
+}
+
+func TestServe_ShutdownDeadline(t *testing.T) {
+	release := make(chan struct{})
+	defer close(release)
+	started := make(chan struct{})
+	handler := http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		close(started)
+		<-release
+	})
+
+	ln, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatalf("Failed to listen: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	served := make(chan error, 1)
+	go func() {
+		served <- serve(ctx, newHTTPServer(ln.Addr().String(), handler, config.Default()), ln, 50*time.Millisecond)
+	}()
+
+	go http.Get("http://" + ln.Addr().String())
+	<-started
+	cancel()
+
+	if err := <-served; err == nil {
+		t.Error("Expected shutdown to fail once the deadline passes with requests in flight")
+	}
+}
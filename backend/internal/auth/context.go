@@ -0,0 +1,26 @@
+package auth
+
+import "context"
+
+// contextKey is unexported so no other package can read or overwrite the identity
+type contextKey struct{}
+
+// currentUserKey is the request context key holding the authenticated user
+var currentUserKey = contextKey{}
+
+// User identifies the authenticated user of a request
+type User struct {
+	ID    string
+	Email string
+}
+
+// WithCurrentUser returns a copy of ctx carrying the authenticated user
+func WithCurrentUser(ctx context.Context, user User) context.Context {
+	return context.WithValue(ctx, currentUserKey, user)
+}
+
+// CurrentUser returns the authenticated user stored in ctx, if any
+func CurrentUser(ctx context.Context) (User, bool) {
+	user, ok := ctx.Value(currentUserKey).(User)
+	return user, ok
+}
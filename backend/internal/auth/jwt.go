@@ -2,7 +2,6 @@ package auth
 
 import (
 	"fmt"
-	"os"
 	"time"
 
 	"github.com/golang-jwt/jwt/v5"
@@ -16,13 +15,26 @@ type Claims struct {
 	jwt.RegisteredClaims
 }
 
+// tokenLifetime is how long a generated token stays valid
+const tokenLifetime = 24 * time.Hour
+
+// TokenManager generates and validates JWT tokens signed with one secret
+type TokenManager struct {
+	secret []byte
+}
+
+// NewTokenManager creates a token manager signing with the given secret
+func NewTokenManager(secret string) *TokenManager {
+	return &TokenManager{secret: []byte(secret)}
+}
+
 // GenerateToken generates a JWT token for the given user
-func GenerateToken(userID, email string) (string, error) {
-	return generateTokenWithExpiration(userID, email, time.Now().Add(24*time.Hour))
+func (m *TokenManager) GenerateToken(userID, email string) (string, error) {
+	return m.generateTokenWithExpiration(userID, email, time.Now().Add(tokenLifetime))
 }
 
 // generateTokenWithExpiration generates a JWT token with custom expiration (for testing)
-func generateTokenWithExpiration(userID, email string, expirationTime time.Time) (string, error) {
+func (m *TokenManager) generateTokenWithExpiration(userID, email string, expirationTime time.Time) (string, error) {
 	claims := Claims{
 		UserID: userID,
 		Email:  email,
@@ -35,12 +47,11 @@ func generateTokenWithExpiration(userID, email string, expirationTime time.Time)
 
 	token := jwt.NewWithClaims(jwt.SigningMethodHS256, claims)
 
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return "", fmt.Errorf("JWT_SECRET environment variable is not set")
+	if len(m.secret) == 0 {
+		return "", fmt.Errorf("JWT secret is not configured")
 	}
 
-	tokenString, err := token.SignedString([]byte(secret))
+	tokenString, err := token.SignedString(m.secret)
 	if err != nil {
 		return "", fmt.Errorf("failed to sign token: %w", err)
 	}
@@ -49,10 +60,9 @@ func generateTokenWithExpiration(userID, email string, expirationTime time.Time)
 }
 
 // ValidateToken validates a JWT token and returns the claims
-func ValidateToken(tokenString string) (*Claims, error) {
-	secret := os.Getenv("JWT_SECRET")
-	if secret == "" {
-		return nil, fmt.Errorf("JWT_SECRET environment variable is not set")
+func (m *TokenManager) ValidateToken(tokenString string) (*Claims, error) {
+	if len(m.secret) == 0 {
+		return nil, fmt.Errorf("JWT secret is not configured")
 	}
 
 	token, err := jwt.ParseWithClaims(tokenString, &Claims{}, func(token *jwt.Token) (interface{}, error) {
@@ -60,7 +70,7 @@ func ValidateToken(tokenString string) (*Claims, error) {
 		if _, ok := token.Method.(*jwt.SigningMethodHMAC); !ok {
 			return nil, fmt.Errorf("unexpected signing method: %v", token.Header["alg"])
 		}
-		return []byte(secret), nil
+		return m.secret, nil
 	})
 
 	if err != nil {
@@ -1,20 +1,17 @@
 package auth
 
 import (
-	"os"
 	"testing"
 	"time"
 )
 
 func TestGenerateToken(t *testing.T) {
-	// Set JWT secret for testing
-	os.Setenv("JWT_SECRET", "test-secret-key")
-	defer os.Unsetenv("JWT_SECRET")
+	tokens := NewTokenManager("test-secret-key")
 
 	userID := "test-user-id"
 	email := "test@example.com"
 
-	token, err := GenerateToken(userID, email)
+	token, err := tokens.GenerateToken(userID, email)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -25,21 +22,19 @@ func TestGenerateToken(t *testing.T) {
 }
 
 func TestValidateToken(t *testing.T) {
-	// Set JWT secret for testing
-	os.Setenv("JWT_SECRET", "test-secret-key")
-	defer os.Unsetenv("JWT_SECRET")
+	tokens := NewTokenManager("test-secret-key")
 
 	userID := "test-user-id"
 	email := "test@example.com"
 
 	// Generate a token
-	token, err := GenerateToken(userID, email)
+	token, err := tokens.GenerateToken(userID, email)
 	if err != nil {
 		t.Fatalf("Failed to generate token: %v", err)
 	}
 
 	// Validate the token
-	claims, err := ValidateToken(token)
+	claims, err := tokens.ValidateToken(token)
 	if err != nil {
 		t.Fatalf("Expected no error, got %v", err)
 	}
@@ -54,38 +49,45 @@ func TestValidateToken(t *testing.T) {
 }
 
 func TestValidateTokenInvalid(t *testing.T) {
-	// Set JWT secret for testing
-	os.Setenv("JWT_SECRET", "test-secret-key")
-	defer os.Unsetenv("JWT_SECRET")
+	tokens := NewTokenManager("test-secret-key")
 
 	invalidToken := "invalid.token.here"
 
-	_, err := ValidateToken(invalidToken)
+	_, err := tokens.ValidateToken(invalidToken)
 	if err == nil {
 		t.Fatal("Expected error for invalid token, got nil")
 	}
 }
 
 func TestValidateTokenExpired(t *testing.T) {
-	// Set JWT secret for testing
-	os.Setenv("JWT_SECRET", "test-secret-key")
-	defer os.Unsetenv("JWT_SECRET")
+	tokens := NewTokenManager("test-secret-key")
 
 	userID := "test-user-id"
 	email := "test@example.com"
 
 	// Generate a token with past expiration time
-	token, err := generateTokenWithExpiration(userID, email, time.Now().Add(-time.Hour))
+	token, err := tokens.generateTokenWithExpiration(userID, email, time.Now().Add(-time.Hour))
 	if err != nil {
 		t.Fatalf("Failed to generate token: %v", err)
 	}
 
-	_, err = ValidateToken(token)
+	_, err = tokens.ValidateToken(token)
 	if err == nil {
 		t.Fatal("Expected error for expired token, got nil")
 	}
 }
 
+func TestValidateTokenWrongSecret(t *testing.T) {
+	token, err := NewTokenManager("test-secret-key").GenerateToken("test-user-id", "test@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	if _, err := NewTokenManager("other-secret-key").ValidateToken(token); err == nil {
+		t.Fatal("Expected error for token signed with another secret, got nil")
+	}
+}
+
 func TestHashPassword(t *testing.T) {
 	password := "testpassword123"
 
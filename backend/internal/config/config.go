@@ -0,0 +1,294 @@
+// Package config loads the server configuration once at startup so that no
+// other package reads the environment directly.
+//
+// Values are applied in order, later sources overriding earlier ones:
+//  1. the defaults returned by Default
+//  2. an optional YAML (.yaml, .yml) or TOML (.toml) file named by CONFIG_FILE
+//  3. environment variables, e.g. DATABASE_URL
+//  4. NAME_FILE variables holding the path of a file with the value, e.g.
+//     JWT_SECRET_FILE for secrets mounted by Docker or ECS
+package config
+
+import (
+	"bytes"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/BurntSushi/toml"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/logging"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/tracing"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// Config holds every setting of the server. The env tag names the environment
+// variable, the yaml and toml tags the key in a configuration file.
+type Config struct {
+	// Port is the TCP port the HTTP server listens on
+	Port string `env:"PORT" yaml:"port" toml:"port"`
+	// DatabaseURL is a SQLite file path or a postgres:// URL
+	DatabaseURL string `env:"DATABASE_URL" yaml:"database_url" toml:"database_url"`
+	// AutoMigrate applies pending migrations before the server starts serving
+	AutoMigrate bool `env:"AUTO_MIGRATE" yaml:"auto_migrate" toml:"auto_migrate"`
+	// DBQueryTimeout bounds every SQL statement, a timed out request gets a 503. Zero disables it.
+	DBQueryTimeout time.Duration `env:"DB_QUERY_TIMEOUT" yaml:"db_query_timeout" toml:"db_query_timeout"`
+
+	// JWTSecret signs and verifies authentication tokens, the server refuses to start without it
+	JWTSecret string `env:"JWT_SECRET" yaml:"jwt_secret" toml:"jwt_secret"`
+
+	// LogLevel is the minimum level logged: debug, info, warn or error
+	LogLevel string `env:"LOG_LEVEL" yaml:"log_level" toml:"log_level"`
+
+	// TracesExporter is none or otlp, OTLPEndpoint the collector URL for otlp,
+	// see tracing.Options. The names are the standard OpenTelemetry variables.
+	TracesExporter string `env:"OTEL_TRACES_EXPORTER" yaml:"otel_traces_exporter" toml:"otel_traces_exporter"`
+	OTLPEndpoint   string `env:"OTEL_EXPORTER_OTLP_ENDPOINT" yaml:"otel_exporter_otlp_endpoint" toml:"otel_exporter_otlp_endpoint"`
+	ServiceName    string `env:"OTEL_SERVICE_NAME" yaml:"otel_service_name" toml:"otel_service_name"`
+
+	// Environment and BuildTimestamp are reported by the health check
+	Environment    string `env:"ENVIRONMENT" yaml:"environment" toml:"environment"`
+	BuildTimestamp string `env:"BUILD_TIMESTAMP" yaml:"build_timestamp" toml:"build_timestamp"`
+
+	// SlugStrategy and SlugMaxLength configure slug generation, see utils.SlugOptions
+	SlugStrategy  string `env:"SLUG_STRATEGY" yaml:"slug_strategy" toml:"slug_strategy"`
+	SlugMaxLength int    `env:"SLUG_MAX_LENGTH" yaml:"slug_max_length" toml:"slug_max_length"`
+
+	// HTTP server timeouts, written like "15s" or "1m30s"
+	HTTPReadHeaderTimeout time.Duration `env:"HTTP_READ_HEADER_TIMEOUT" yaml:"http_read_header_timeout" toml:"http_read_header_timeout"`
+	HTTPReadTimeout       time.Duration `env:"HTTP_READ_TIMEOUT" yaml:"http_read_timeout" toml:"http_read_timeout"`
+	HTTPWriteTimeout      time.Duration `env:"HTTP_WRITE_TIMEOUT" yaml:"http_write_timeout" toml:"http_write_timeout"`
+	HTTPIdleTimeout       time.Duration `env:"HTTP_IDLE_TIMEOUT" yaml:"http_idle_timeout" toml:"http_idle_timeout"`
+	// ShutdownTimeout bounds draining in-flight requests after SIGTERM.
+	// The default leaves headroom within the 30 seconds ECS waits before SIGKILL.
+	ShutdownTimeout time.Duration `env:"SHUTDOWN_TIMEOUT" yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+// Default returns the configuration used when nothing overrides it
+func Default() *Config {
+	slugs := utils.DefaultSlugOptions()
+
+	return &Config{
+		Port:                  "8080",
+		DatabaseURL:           "./data/conduit.db",
+		DBQueryTimeout:        db.DefaultQueryTimeout,
+		LogLevel:              "info",
+		TracesExporter:        string(tracing.ExporterNone),
+		ServiceName:           "conduit-api",
+		SlugStrategy:          string(slugs.Strategy),
+		SlugMaxLength:         slugs.MaxLength,
+		HTTPReadHeaderTimeout: 5 * time.Second,
+		HTTPReadTimeout:       15 * time.Second,
+		HTTPWriteTimeout:      30 * time.Second,
+		HTTPIdleTimeout:       120 * time.Second,
+		ShutdownTimeout:       20 * time.Second,
+	}
+}
+
+// Load reads the configuration file named by CONFIG_FILE, if any, and the
+// environment on top of the defaults. It fails on malformed values but does not
+// check that required settings are present, see Validate.
+func Load() (*Config, error) {
+	cfg := Default()
+
+	if path := os.Getenv("CONFIG_FILE"); path != "" {
+		if err := cfg.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := cfg.loadEnv(os.Getenv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// Validate checks the settings the server needs to start
+func (c *Config) Validate() error {
+	if c.JWTSecret == "" {
+		return fmt.Errorf("JWT_SECRET is required")
+	}
+
+	if _, err := strconv.ParseUint(c.Port, 10, 16); err != nil {
+		return fmt.Errorf("invalid PORT: %q", c.Port)
+	}
+
+	if _, err := logging.ParseLevel(c.LogLevel); err != nil {
+		return fmt.Errorf("invalid LOG_LEVEL: %w", err)
+	}
+
+	if _, err := tracing.ParseExporter(c.TracesExporter); err != nil {
+		return fmt.Errorf("invalid OTEL_TRACES_EXPORTER: %w", err)
+	}
+
+	if _, err := utils.ParseSlugStrategy(c.SlugStrategy); err != nil {
+		return fmt.Errorf("invalid SLUG_STRATEGY: %w", err)
+	}
+	if c.SlugMaxLength < 0 {
+		return fmt.Errorf("invalid SLUG_MAX_LENGTH: %d", c.SlugMaxLength)
+	}
+
+	for name, timeout := range map[string]time.Duration{
+		"HTTP_READ_HEADER_TIMEOUT": c.HTTPReadHeaderTimeout,
+		"HTTP_READ_TIMEOUT":        c.HTTPReadTimeout,
+		"HTTP_WRITE_TIMEOUT":       c.HTTPWriteTimeout,
+		"HTTP_IDLE_TIMEOUT":        c.HTTPIdleTimeout,
+		"SHUTDOWN_TIMEOUT":         c.ShutdownTimeout,
+		"DB_QUERY_TIMEOUT":         c.DBQueryTimeout,
+	} {
+		if timeout < 0 {
+			return fmt.Errorf("invalid %s: %s", name, timeout)
+		}
+	}
+
+	return nil
+}
+
+// Level returns the configured log level
+func (c *Config) Level() slog.Level {
+	// Validate has checked the level
+	level, _ := logging.ParseLevel(c.LogLevel)
+	return level
+}
+
+// TracingOptions returns the tracing options configured by the OTEL_* settings
+func (c *Config) TracingOptions() tracing.Options {
+	// Validate has checked the exporter
+	exporter, _ := tracing.ParseExporter(c.TracesExporter)
+	return tracing.Options{
+		Exporter:    exporter,
+		Endpoint:    c.OTLPEndpoint,
+		ServiceName: c.ServiceName,
+	}
+}
+
+// SlugOptions returns the slug options configured by SlugStrategy and SlugMaxLength
+func (c *Config) SlugOptions() utils.SlugOptions {
+	opts := utils.DefaultSlugOptions()
+	// Validate has checked the strategy
+	opts.Strategy, _ = utils.ParseSlugStrategy(c.SlugStrategy)
+	opts.MaxLength = c.SlugMaxLength
+	return opts
+}
+
+// loadFile decodes a YAML or TOML file, chosen by extension, into c.
+// Keys missing from the file keep their current value and unknown keys are an error.
+func (c *Config) loadFile(path string) error {
+	content, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := strings.ToLower(filepath.Ext(path)); ext {
+	case ".yaml", ".yml":
+		decoder := yaml.NewDecoder(bytes.NewReader(content))
+		decoder.KnownFields(true)
+		// An empty file decodes to io.EOF and leaves the defaults alone
+		if err := decoder.Decode(c); err != nil && len(bytes.TrimSpace(content)) > 0 {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	case ".toml":
+		meta, err := toml.Decode(string(content), c)
+		if err != nil {
+			return fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+		if undecoded := meta.Undecoded(); len(undecoded) > 0 {
+			return fmt.Errorf("failed to parse %s: unknown key %q", path, undecoded[0].String())
+		}
+	default:
+		return fmt.Errorf("unsupported config file extension %q, expected .yaml, .yml or .toml", ext)
+	}
+
+	return nil
+}
+
+// loadEnv sets every field whose variable, or its _FILE variant, is set
+func (c *Config) loadEnv(getenv func(string) string) error {
+	v := reflect.ValueOf(c).Elem()
+	t := v.Type()
+
+	for i := 0; i < t.NumField(); i++ {
+		name := t.Field(i).Tag.Get("env")
+		if name == "" {
+			continue
+		}
+
+		value, ok, err := lookupValue(getenv, name)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		if err := setField(v.Field(i), value); err != nil {
+			return fmt.Errorf("invalid %s: %q", name, value)
+		}
+	}
+
+	return nil
+}
+
+// lookupValue returns the value of the variable name, or the content of the file
+// named by name_FILE with surrounding whitespace removed. Empty variables count as
+// unset. Setting both is an error because it is unclear which one is meant.
+func lookupValue(getenv func(string) string, name string) (string, bool, error) {
+	value, path := getenv(name), getenv(name+"_FILE")
+	hasValue, hasFile := value != "", path != ""
+
+	switch {
+	case hasValue && hasFile:
+		return "", false, fmt.Errorf("both %s and %s_FILE are set", name, name)
+	case hasFile:
+		content, err := os.ReadFile(path)
+		if err != nil {
+			return "", false, fmt.Errorf("failed to read %s_FILE: %w", name, err)
+		}
+		return strings.TrimSpace(string(content)), true, nil
+	default:
+		return value, hasValue, nil
+	}
+}
+
+// durationType is handled before int64, which it shares a kind with
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// setField parses value into the string, bool, int or duration field
+func setField(field reflect.Value, value string) error {
+	if field.Type() == durationType {
+		d, err := time.ParseDuration(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(d))
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.String:
+		field.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return err
+		}
+		field.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return err
+		}
+		field.SetInt(int64(n))
+	default:
+		panic(fmt.Sprintf("config: unsupported field type %s", field.Type()))
+	}
+
+	return nil
+}
@@ -0,0 +1,194 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+)
+
+// clearEnv unsets every configuration variable for the duration of the test
+func clearEnv(t *testing.T) {
+	t.Setenv("CONFIG_FILE", "")
+
+	fields := reflect.TypeOf(Config{})
+	for i := 0; i < fields.NumField(); i++ {
+		if name := fields.Field(i).Tag.Get("env"); name != "" {
+			t.Setenv(name, "")
+			t.Setenv(name+"_FILE", "")
+		}
+	}
+}
+
+func writeFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	if err := os.WriteFile(path, []byte(content), 0600); err != nil {
+		t.Fatalf("Failed to write %s: %v", name, err)
+	}
+	return path
+}
+
+func TestLoad_Defaults(t *testing.T) {
+	clearEnv(t)
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if *cfg != *Default() {
+		t.Errorf("Expected the defaults, got %+v", cfg)
+	}
+}
+
+func TestLoad_Env(t *testing.T) {
+	clearEnv(t)
+
+	t.Setenv("PORT", "9090")
+	t.Setenv("AUTO_MIGRATE", "true")
+	t.Setenv("SLUG_MAX_LENGTH", "40")
+	t.Setenv("HTTP_WRITE_TIMEOUT", "1m")
+	// Empty variables keep the default
+	t.Setenv("DATABASE_URL", "")
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+
+	if cfg.Port != "9090" || !cfg.AutoMigrate || cfg.SlugMaxLength != 40 || cfg.HTTPWriteTimeout != time.Minute {
+		t.Errorf("Expected the variables to apply, got %+v", cfg)
+	}
+	if cfg.DatabaseURL != Default().DatabaseURL {
+		t.Errorf("Expected the default database URL, got %q", cfg.DatabaseURL)
+	}
+}
+
+func TestLoad_InvalidEnv(t *testing.T) {
+	clearEnv(t)
+
+	tests := map[string]string{
+		"AUTO_MIGRATE":      "sometimes",
+		"SLUG_MAX_LENGTH":   "long",
+		"HTTP_IDLE_TIMEOUT": "soon",
+		"DB_QUERY_TIMEOUT":  "forever",
+	}
+
+	for name, value := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv(name, value)
+
+			if _, err := Load(); err == nil || !strings.Contains(err.Error(), name) {
+				t.Errorf("Expected an error naming %s, got %v", name, err)
+			}
+		})
+	}
+}
+
+func TestLoad_SecretFile(t *testing.T) {
+	clearEnv(t)
+
+	t.Setenv("JWT_SECRET_FILE", writeFile(t, "jwt_secret", "from-file\n"))
+
+	cfg, err := Load()
+	if err != nil {
+		t.Fatalf("Failed to load configuration: %v", err)
+	}
+	if cfg.JWTSecret != "from-file" {
+		t.Errorf("Expected the secret from the file without the newline, got %q", cfg.JWTSecret)
+	}
+
+	t.Setenv("JWT_SECRET", "from-env")
+	if _, err := Load(); err == nil {
+		t.Error("Expected setting both JWT_SECRET and JWT_SECRET_FILE to fail")
+	}
+}
+
+func TestLoad_File(t *testing.T) {
+	clearEnv(t)
+
+	tests := map[string]string{
+		"config.yaml": "port: \"9090\"\njwt_secret: from-file\nslug_strategy: unicode\nshutdown_timeout: 5s\n",
+		"config.toml": "port = \"9090\"\njwt_secret = \"from-file\"\nslug_strategy = \"unicode\"\nshutdown_timeout = \"5s\"\n",
+	}
+
+	for name, content := range tests {
+		t.Run(name, func(t *testing.T) {
+			t.Setenv("CONFIG_FILE", writeFile(t, name, content))
+			// The environment overrides the file
+			t.Setenv("PORT", "7070")
+
+			cfg, err := Load()
+			if err != nil {
+				t.Fatalf("Failed to load configuration: %v", err)
+			}
+
+			if cfg.Port != "7070" {
+				t.Errorf("Expected PORT to override the file, got %q", cfg.Port)
+			}
+			if cfg.JWTSecret != "from-file" || cfg.SlugStrategy != "unicode" || cfg.ShutdownTimeout != 5*time.Second {
+				t.Errorf("Expected the file values to apply, got %+v", cfg)
+			}
+			if cfg.HTTPReadTimeout != Default().HTTPReadTimeout {
+				t.Errorf("Expected keys missing from the file to keep their default, got %v", cfg.HTTPReadTimeout)
+			}
+		})
+	}
+}
+
+func TestLoad_InvalidFile(t *testing.T) {
+	clearEnv(t)
+
+	tests := map[string]string{
+		"unknown yaml key": "config.yaml:prot: 9090\n",
+		"unknown toml key": "config.toml:prot = 9090\n",
+		"bad extension":    "config.json:{}",
+	}
+
+	for name, file := range tests {
+		t.Run(name, func(t *testing.T) {
+			fileName, content, _ := strings.Cut(file, ":")
+			t.Setenv("CONFIG_FILE", writeFile(t, fileName, content))
+
+			if _, err := Load(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestConfig_Validate(t *testing.T) {
+	valid := func() *Config {
+		cfg := Default()
+		cfg.JWTSecret = "secret"
+		return cfg
+	}
+
+	if err := valid().Validate(); err != nil {
+		t.Fatalf("Expected a valid configuration, got %v", err)
+	}
+
+	tests := map[string]func(*Config){
+		"missing JWT secret":      func(c *Config) { c.JWTSecret = "" },
+		"non-numeric port":        func(c *Config) { c.Port = "http" },
+		"unknown log level":       func(c *Config) { c.LogLevel = "loud" },
+		"unknown traces exporter": func(c *Config) { c.TracesExporter = "zipkin" },
+		"unknown slug strategy":   func(c *Config) { c.SlugStrategy = "emoji" },
+		"negative slug length":    func(c *Config) { c.SlugMaxLength = -1 },
+		"negative write timeout":  func(c *Config) { c.HTTPWriteTimeout = -time.Second },
+		"negative query timeout":  func(c *Config) { c.DBQueryTimeout = -time.Second },
+	}
+
+	for name, modify := range tests {
+		t.Run(name, func(t *testing.T) {
+			cfg := valid()
+			modify(cfg)
+
+			if err := cfg.Validate(); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"strings"
@@ -12,19 +13,29 @@ import (
 
 // ArticleRepository handles article data operations
 type ArticleRepository struct {
-	db *sql.DB
+	db *conn
 }
 
 // NewArticleRepository creates a new article repository
 func NewArticleRepository(db *sql.DB) *ArticleRepository {
-	return &ArticleRepository{db: db}
+	return &ArticleRepository{db: newConn(db)}
 }
 
-// Create creates a new article
-func (r *ArticleRepository) Create(article *models.Article) error {
+// Create creates a new article and its tags in a single transaction
+func (r *ArticleRepository) Create(ctx context.Context, article *models.Article) error {
+	ctx, span := startSpan(ctx, "ArticleRepository.Create")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
 	// Generate unique slug
 	slug := utils.GenerateSlug(article.Title)
-	existingSlugs, err := r.getSimilarSlugs(slug)
+	existingSlugs, err := getSimilarSlugs(ctx, tx, slug, "")
 	if err != nil {
 		return fmt.Errorf("failed to check existing slugs: %w", err)
 	}
@@ -35,7 +46,7 @@ func (r *ArticleRepository) Create(article *models.Article) error {
 
 	// Get author ID from username (assuming this is passed correctly)
 	var authorID string
-	err = r.db.QueryRow("SELECT id FROM users WHERE username = ?", article.Author.Username).Scan(&authorID)
+	err = tx.QueryRow(ctx, "SELECT id FROM users WHERE username = ?", article.Author.Username).Scan(&authorID)
 	if err != nil {
 		return fmt.Errorf("failed to get author ID: %w", err)
 	}
@@ -46,7 +57,7 @@ func (r *ArticleRepository) Create(article *models.Article) error {
 		RETURNING id
 	`
 
-	row := r.db.QueryRow(query, article.Slug, article.Title, article.Description,
+	row := tx.QueryRow(ctx, query, article.Slug, article.Title, article.Description,
 		article.Body, authorID, article.CreatedAt, article.UpdatedAt)
 
 	err = row.Scan(&article.ID)
@@ -56,30 +67,41 @@ func (r *ArticleRepository) Create(article *models.Article) error {
 
 	// Handle tags
 	if len(article.TagList) > 0 {
-		if err := r.saveTags(article.ID, article.TagList); err != nil {
+		if article.TagList, err = saveTags(ctx, tx, article.ID, article.TagList); err != nil {
 			return fmt.Errorf("failed to save tags: %w", err)
 		}
 	}
 
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit article: %w", err)
+	}
+
 	return nil
 }
 
-// GetBySlug retrieves an article by its slug
-func (r *ArticleRepository) GetBySlug(slug string) (*models.Article, error) {
-	query := `
-		SELECT a.id, a.slug, a.title, a.description, a.body, a.created_at, a.updated_at,
-		       u.username, u.bio, u.image,
-		       COUNT(f.user_id) as favorites_count
-		FROM articles a
-		JOIN users u ON a.author_id = u.id
-		LEFT JOIN favorites f ON a.id = f.article_id
-		WHERE a.slug = ?
-		GROUP BY a.id, u.id
-	`
+// articleSelect selects an article with its author and per-viewer fields.
+// Favorites are counted in a subquery so joins never multiply the count.
+// It expects the viewer ID twice as its first two parameters.
+const articleSelect = `
+	SELECT a.id, a.slug, a.title, a.description, a.body, a.created_at, a.updated_at,
+	       u.username, u.bio, u.image,
+	       (SELECT COUNT(*) FROM favorites f WHERE f.article_id = a.id) as favorites_count,
+	       EXISTS(SELECT 1 FROM favorites fv WHERE fv.user_id = ? AND fv.article_id = a.id) as favorited,
+	       EXISTS(SELECT 1 FROM follows fo WHERE fo.follower_id = ? AND fo.following_id = u.id) as following
+	FROM articles a
+	JOIN users u ON a.author_id = u.id
+`
+
+// rowScanner is implemented by both *timedRow and *timedRows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
 
+// scanArticle scans a row selected with articleSelect
+func scanArticle(row rowScanner) (models.Article, error) {
 	var article models.Article
 	var author models.User
-	row := r.db.QueryRow(query, slug)
+	var following bool
 
 	err := row.Scan(
 		&article.ID,
@@ -93,43 +115,70 @@ func (r *ArticleRepository) GetBySlug(slug string) (*models.Article, error) {
 		&author.Bio,
 		&author.Image,
 		&article.FavoritesCount,
+		&article.Favorited,
+		&following,
 	)
+	if err != nil {
+		return article, err
+	}
+
+	// Set author info
+	article.Author = models.Author{
+		Username:  author.Username,
+		Bio:       author.Bio,
+		Image:     author.Image,
+		Following: following,
+	}
 
+	return article, nil
+}
+
+// GetBySlug retrieves an article by its slug.
+// viewerID is the authenticated user the article is rendered for and may be empty.
+func (r *ArticleRepository) GetBySlug(ctx context.Context, slug, viewerID string) (*models.Article, error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetBySlug")
+	defer span.End()
+
+	query := articleSelect + ` WHERE a.slug = ?`
+
+	article, err := scanArticle(r.db.QueryRow(ctx, query, viewerID, viewerID, slug))
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("article not found")
+			return nil, notFoundError("article")
 		}
 		return nil, fmt.Errorf("failed to get article by slug: %w", err)
 	}
 
 	// Load tags
-	tags, err := r.getArticleTags(article.ID)
-	if err != nil {
+	articles := []models.Article{article}
+	if err := r.loadTags(ctx, articles); err != nil {
 		return nil, fmt.Errorf("failed to load tags: %w", err)
 	}
-	article.TagList = tags
-
-	// Set author info
-	article.Author = models.Author{
-		Username:  author.Username,
-		Bio:       author.Bio,
-		Image:     author.Image,
-		Following: false, // TODO: Implement following logic
-	}
 
-	return &article, nil
+	return &articles[0], nil
 }
 
-// GetAll retrieves articles with filtering and pagination
-func (r *ArticleRepository) GetAll(filter models.ArticleFilter) ([]models.Article, int, error) {
+// GetAll retrieves articles with filtering and pagination.
+// viewerID is the authenticated user the articles are rendered for and may be empty.
+//
+// A page is loaded with a constant number of queries: one count, one for the
+// articles and one batched query for the tags of every article on the page.
+func (r *ArticleRepository) GetAll(ctx context.Context, filter models.ArticleFilter, viewerID string) ([]models.Article, int, error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetAll")
+	defer span.End()
+
 	whereClause := ""
 	args := []interface{}{}
 
-	// Build WHERE clause
+	// Build WHERE clause. Filters on related tables use subqueries so that
+	// every article appears at most once without DISTINCT or GROUP BY.
 	conditions := []string{}
 
 	if filter.Tag != "" {
-		conditions = append(conditions, "t.name = ?")
+		conditions = append(conditions, `a.id IN (
+			SELECT at.article_id FROM article_tags at
+			JOIN tags t ON at.tag_id = t.id
+			WHERE t.name = ?)`)
 		args = append(args, filter.Tag)
 	}
 
@@ -138,88 +187,61 @@ func (r *ArticleRepository) GetAll(filter models.ArticleFilter) ([]models.Articl
 		args = append(args, filter.Author)
 	}
 
+	if filter.Favorited != "" {
+		conditions = append(conditions, `a.id IN (
+			SELECT fv.article_id FROM favorites fv
+			JOIN users fu ON fv.user_id = fu.id
+			WHERE fu.username = ?)`)
+		args = append(args, filter.Favorited)
+	}
+
+	if filter.FollowedBy != "" {
+		conditions = append(conditions, "a.author_id IN (SELECT following_id FROM follows WHERE follower_id = ?)")
+		args = append(args, filter.FollowedBy)
+	}
+
 	if len(conditions) > 0 {
 		whereClause = " WHERE " + strings.Join(conditions, " AND ")
 	}
 
 	// Count query
 	countQuery := fmt.Sprintf(`
-		SELECT COUNT(DISTINCT a.id)
+		SELECT COUNT(*)
 		FROM articles a
 		JOIN users u ON a.author_id = u.id
-		LEFT JOIN article_tags at ON a.id = at.article_id
-		LEFT JOIN tags t ON at.tag_id = t.id
 		%s
 	`, whereClause)
 
 	var totalCount int
-	err := r.db.QueryRow(countQuery, args...).Scan(&totalCount)
+	err := r.db.QueryRow(ctx, countQuery, args...).Scan(&totalCount)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to count articles: %w", err)
 	}
 
 	// Main query
-	query := fmt.Sprintf(`
-		SELECT DISTINCT a.id, a.slug, a.title, a.description, a.body, a.created_at, a.updated_at,
-		       u.username, u.bio, u.image,
-		       COUNT(f.user_id) as favorites_count
-		FROM articles a
-		JOIN users u ON a.author_id = u.id
-		LEFT JOIN article_tags at ON a.id = at.article_id
-		LEFT JOIN tags t ON at.tag_id = t.id
-		LEFT JOIN favorites f ON a.id = f.article_id
-		%s
-		GROUP BY a.id, u.id
+	query := articleSelect + whereClause + `
 		ORDER BY a.created_at DESC
 		LIMIT ? OFFSET ?
-	`, whereClause)
+	`
 
-	// Add pagination parameters
-	args = append(args, filter.Limit, filter.Offset)
+	// The viewer parameters precede the filter parameters, pagination follows them
+	queryArgs := append([]interface{}{viewerID, viewerID}, args...)
+	queryArgs = append(queryArgs, filter.Limit, filter.Offset)
 
-	rows, err := r.db.Query(query, args...)
+	rows, err := r.db.Query(ctx, query, queryArgs...)
 	if err != nil {
 		return nil, 0, fmt.Errorf("failed to query articles: %w", err)
 	}
 	defer rows.Close()
 
-	var articles []models.Article
+	// Initialize with empty slice to ensure JSON serializes as [] instead of null
+	articles := make([]models.Article, 0)
 	for rows.Next() {
-		var article models.Article
-		var author models.User
-
-		err := rows.Scan(
-			&article.ID,
-			&article.Slug,
-			&article.Title,
-			&article.Description,
-			&article.Body,
-			&article.CreatedAt,
-			&article.UpdatedAt,
-			&author.Username,
-			&author.Bio,
-			&author.Image,
-			&article.FavoritesCount,
-		)
+		article, err := scanArticle(rows)
 		if err != nil {
 			return nil, 0, fmt.Errorf("failed to scan article: %w", err)
 		}
 
-		// Load tags for each article
-		tags, err := r.getArticleTags(article.ID)
-		if err != nil {
-			return nil, 0, fmt.Errorf("failed to load tags for article %s: %w", article.ID, err)
-		}
-		article.TagList = tags
-
-		// Set author info
-		article.Author = models.Author{
-			Username:  author.Username,
-			Bio:       author.Bio,
-			Image:     author.Image,
-			Following: false, // TODO: Implement following logic
-		}
-
 		articles = append(articles, article)
 	}
 
@@ -227,65 +249,150 @@ func (r *ArticleRepository) GetAll(filter models.ArticleFilter) ([]models.Articl
 		return nil, 0, fmt.Errorf("error iterating articles: %w", err)
 	}
 
+	// Release the connection before loading tags
+	rows.Close()
+
+	// Load tags for the whole page at once
+	if err := r.loadTags(ctx, articles); err != nil {
+		return nil, 0, fmt.Errorf("failed to load tags: %w", err)
+	}
+
 	return articles, totalCount, nil
 }
 
-// Update updates an existing article
-func (r *ArticleRepository) Update(slug string, article *models.Article) error {
+// GetFeed retrieves articles authored by users the given user follows, most recent first
+func (r *ArticleRepository) GetFeed(ctx context.Context, userID string, limit, offset int) ([]models.Article, int, error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.GetFeed")
+	defer span.End()
+
+	filter := models.ArticleFilter{
+		FollowedBy: userID,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	return r.GetAll(ctx, filter, userID)
+}
+
+// Update updates an existing article in a single transaction.
+// Empty string fields are left unchanged. A nil TagList keeps the current tags,
+// while a non-nil TagList (even empty) replaces them.
+// The slug is regenerated only when the title changes.
+// On success article.Slug holds the article's current slug.
+func (r *ArticleRepository) Update(ctx context.Context, slug string, article *models.Article) error {
+	ctx, span := startSpan(ctx, "ArticleRepository.Update")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
 	article.UpdatedAt = time.Now()
+	article.Slug = ""
 
-	// If title changed, regenerate slug
-	if article.Title != "" {
-		newSlug := utils.GenerateSlug(article.Title)
-		if newSlug != slug {
-			existingSlugs, err := r.getSimilarSlugs(newSlug)
-			if err != nil {
-				return fmt.Errorf("failed to check existing slugs: %w", err)
-			}
-			article.Slug = utils.GenerateUniqueSlug(article.Title, existingSlugs)
+	var articleID, storedTitle string
+	err = tx.QueryRow(ctx, `SELECT id, title FROM articles WHERE slug = ?`, slug).Scan(&articleID, &storedTitle)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFoundError("article")
+		}
+		return fmt.Errorf("failed to get article: %w", err)
+	}
+
+	// Regenerate the slug only when the title changed, so that saving an
+	// unchanged title keeps a suffixed slug such as foo-1
+	if article.Title != "" && strings.TrimSpace(article.Title) != strings.TrimSpace(storedTitle) {
+		// The article's own slugs are free to take again
+		existingSlugs, err := getSimilarSlugs(ctx, tx, utils.GenerateSlug(article.Title), articleID)
+		if err != nil {
+			return fmt.Errorf("failed to check existing slugs: %w", err)
+		}
+		if newSlug := utils.GenerateUniqueSlug(article.Title, existingSlugs); newSlug != slug {
+			article.Slug = newSlug
 		}
 	}
 
+	// A slug the article had before moves out of its history
+	if article.Slug != "" {
+		if _, err := tx.Exec(ctx, `DELETE FROM article_slug_history WHERE slug = ?`, article.Slug); err != nil {
+			return fmt.Errorf("failed to update slug history: %w", err)
+		}
+	}
+
+	// NULLIF turns omitted (empty) fields into NULL so COALESCE keeps the stored value
 	query := `
-		UPDATE articles 
-		SET title = COALESCE(?, title),
-		    description = COALESCE(?, description),
-		    body = COALESCE(?, body),
-		    slug = COALESCE(?, slug),
+		UPDATE articles
+		SET title = COALESCE(NULLIF(?, ''), title),
+		    description = COALESCE(NULLIF(?, ''), description),
+		    body = COALESCE(NULLIF(?, ''), body),
+		    slug = COALESCE(NULLIF(?, ''), slug),
 		    updated_at = ?
 		WHERE slug = ?
+		RETURNING slug
 	`
 
-	result, err := r.db.Exec(query,
+	err = tx.QueryRow(ctx, query,
 		article.Title,
 		article.Description,
 		article.Body,
 		article.Slug,
 		article.UpdatedAt,
 		slug,
-	)
+	).Scan(&article.Slug)
 
 	if err != nil {
+		if err == sql.ErrNoRows {
+			return notFoundError("article")
+		}
 		return fmt.Errorf("failed to update article: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	// Keep the previous slug so existing links resolve to the renamed article
+	if article.Slug != slug {
+		if _, err := tx.Exec(ctx,
+			`INSERT INTO article_slug_history (slug, article_id, created_at) VALUES (?, ?, ?)`,
+			slug, articleID, article.UpdatedAt,
+		); err != nil {
+			return fmt.Errorf("failed to record slug history: %w", err)
+		}
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
+	// Replace tags when a tag list was provided
+	if article.TagList != nil {
+		if article.TagList, err = saveTags(ctx, tx, articleID, article.TagList); err != nil {
+			return fmt.Errorf("failed to save tags: %w", err)
+		}
+
+		if _, err := deleteOrphanTags(ctx, tx); err != nil {
+			return err
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit article update: %w", err)
 	}
 
 	return nil
 }
 
-// Delete deletes an article by slug
-func (r *ArticleRepository) Delete(slug string) error {
+// Delete deletes an article by slug along with tags no other article uses
+func (r *ArticleRepository) Delete(ctx context.Context, slug string) error {
+	ctx, span := startSpan(ctx, "ArticleRepository.Delete")
+	defer span.End()
+
+	tx, err := r.db.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
 	query := `DELETE FROM articles WHERE slug = ?`
 
-	result, err := r.db.Exec(query, slug)
+	result, err := tx.Exec(ctx, query, slug)
 	if err != nil {
 		return fmt.Errorf("failed to delete article: %w", err)
 	}
@@ -296,19 +403,115 @@ func (r *ArticleRepository) Delete(slug string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("article not found")
+		return notFoundError("article")
+	}
+
+	// Article tags are removed by ON DELETE CASCADE, drop tags left without articles
+	if _, err := deleteOrphanTags(ctx, tx); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
+
+// Favorite marks an article as favorited by the given user. Favoriting twice is a no-op.
+func (r *ArticleRepository) Favorite(ctx context.Context, slug, userID string) error {
+	ctx, span := startSpan(ctx, "ArticleRepository.Favorite")
+	defer span.End()
+
+	articleID, err := r.getArticleIDBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	query := `
+		INSERT INTO favorites (user_id, article_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (user_id, article_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(ctx, query, userID, articleID, time.Now()); err != nil {
+		return fmt.Errorf("failed to favorite article: %w", err)
+	}
+
+	return nil
+}
+
+// Unfavorite removes the given user's favorite from an article. Unfavoriting twice is a no-op.
+func (r *ArticleRepository) Unfavorite(ctx context.Context, slug, userID string) error {
+	ctx, span := startSpan(ctx, "ArticleRepository.Unfavorite")
+	defer span.End()
+
+	articleID, err := r.getArticleIDBySlug(ctx, slug)
+	if err != nil {
+		return err
+	}
+
+	query := `DELETE FROM favorites WHERE user_id = ? AND article_id = ?`
+
+	if _, err := r.db.Exec(ctx, query, userID, articleID); err != nil {
+		return fmt.Errorf("failed to unfavorite article: %w", err)
 	}
 
 	return nil
 }
 
+// ResolveSlug returns the current slug of the article identified by a current
+// or previous slug
+func (r *ArticleRepository) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	ctx, span := startSpan(ctx, "ArticleRepository.ResolveSlug")
+	defer span.End()
+
+	var currentSlug string
+	query := `SELECT slug FROM articles WHERE id = (` + articleIDBySlugQuery + `)`
+
+	err := r.db.QueryRow(ctx, query, slug, slug).Scan(&currentSlug)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", notFoundError("article")
+		}
+		return "", fmt.Errorf("failed to resolve slug: %w", err)
+	}
+
+	return currentSlug, nil
+}
+
 // Helper methods
 
-// getSimilarSlugs gets all slugs that start with the given base slug
-func (r *ArticleRepository) getSimilarSlugs(baseSlug string) ([]string, error) {
-	query := `SELECT slug FROM articles WHERE slug LIKE ?`
+// articleIDBySlugQuery selects the ID of the article with a current or previous slug.
+// It takes the slug twice. Current slugs and previous slugs never overlap because
+// new slugs are generated to be unique across both.
+const articleIDBySlugQuery = `
+	SELECT id FROM articles WHERE slug = ?
+	UNION ALL
+	SELECT article_id FROM article_slug_history WHERE slug = ?
+	LIMIT 1`
+
+// getArticleIDBySlug gets an article ID by its current or previous slug
+func (r *ArticleRepository) getArticleIDBySlug(ctx context.Context, slug string) (string, error) {
+	var articleID string
+
+	err := r.db.QueryRow(ctx, articleIDBySlugQuery, slug, slug).Scan(&articleID)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return "", notFoundError("article")
+		}
+		return "", fmt.Errorf("failed to get article ID: %w", err)
+	}
+
+	return articleID, nil
+}
+
+// getSimilarSlugs gets all current and previous slugs that start with the given
+// base slug, except those of the article with excludeID
+func getSimilarSlugs(ctx context.Context, db dbtx, baseSlug, excludeID string) ([]string, error) {
+	query := `
+		SELECT slug FROM articles WHERE slug LIKE ? AND id <> ?
+		UNION
+		SELECT slug FROM article_slug_history WHERE slug LIKE ? AND article_id <> ?
+	`
 
-	rows, err := r.db.Query(query, baseSlug+"%")
+	rows, err := db.Query(ctx, query, baseSlug+"%", excludeID, baseSlug+"%", excludeID)
 	if err != nil {
 		return nil, err
 	}
@@ -326,39 +529,50 @@ func (r *ArticleRepository) getSimilarSlugs(baseSlug string) ([]string, error) {
 	return slugs, rows.Err()
 }
 
-// saveTags saves tags for an article
-func (r *ArticleRepository) saveTags(articleID string, tagNames []string) error {
+// saveTags replaces the tags of an article and returns the saved tag list.
+// Blank and duplicate tag names are skipped.
+func saveTags(ctx context.Context, db dbtx, articleID string, tagNames []string) ([]string, error) {
 	// First, delete existing tags for this article
-	_, err := r.db.Exec(`DELETE FROM article_tags WHERE article_id = ?`, articleID)
+	_, err := db.Exec(ctx, `DELETE FROM article_tags WHERE article_id = ?`, articleID)
 	if err != nil {
-		return err
+		return nil, err
 	}
 
+	saved := make([]string, 0, len(tagNames))
+	seen := make(map[string]bool, len(tagNames))
 	for _, tagName := range tagNames {
+		tagName = strings.TrimSpace(tagName)
+		if tagName == "" || seen[tagName] {
+			continue
+		}
+		seen[tagName] = true
+
 		// Get or create tag
-		tagID, err := r.getOrCreateTag(tagName)
+		tagID, err := getOrCreateTag(ctx, db, tagName)
 		if err != nil {
-			return err
+			return nil, err
 		}
 
 		// Associate tag with article
-		_, err = r.db.Exec(`
-			INSERT INTO article_tags (article_id, tag_id) 
+		_, err = db.Exec(ctx, `
+			INSERT INTO article_tags (article_id, tag_id)
 			VALUES (?, ?)
 		`, articleID, tagID)
 		if err != nil {
-			return err
+			return nil, err
 		}
+
+		saved = append(saved, tagName)
 	}
 
-	return nil
+	return saved, nil
 }
 
 // getOrCreateTag gets existing tag or creates a new one
-func (r *ArticleRepository) getOrCreateTag(name string) (string, error) {
+func getOrCreateTag(ctx context.Context, db dbtx, name string) (string, error) {
 	// Try to get existing tag
 	var tagID string
-	err := r.db.QueryRow(`SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID)
+	err := db.QueryRow(ctx, `SELECT id FROM tags WHERE name = ?`, name).Scan(&tagID)
 	if err == nil {
 		return tagID, nil
 	}
@@ -368,39 +582,57 @@ func (r *ArticleRepository) getOrCreateTag(name string) (string, error) {
 	}
 
 	// Create new tag
-	err = r.db.QueryRow(`
-		INSERT INTO tags (name, created_at) 
-		VALUES (?, ?) 
+	err = db.QueryRow(ctx, `
+		INSERT INTO tags (name, created_at)
+		VALUES (?, ?)
 		RETURNING id
 	`, name, time.Now()).Scan(&tagID)
 
 	return tagID, err
 }
 
-// getArticleTags gets all tags for an article
-func (r *ArticleRepository) getArticleTags(articleID string) ([]string, error) {
-	query := `
-		SELECT t.name 
-		FROM tags t
-		JOIN article_tags at ON t.id = at.tag_id
-		WHERE at.article_id = ?
+// loadTags sets the tag list of every given article using a single query
+func (r *ArticleRepository) loadTags(ctx context.Context, articles []models.Article) error {
+	if len(articles) == 0 {
+		return nil
+	}
+
+	placeholders := make([]string, len(articles))
+	args := make([]interface{}, len(articles))
+	indexByID := make(map[string]int, len(articles))
+	for i, article := range articles {
+		placeholders[i] = "?"
+		args[i] = article.ID
+		indexByID[article.ID] = i
+
+		// Ensure articles without tags serialize as [] instead of null
+		articles[i].TagList = make([]string, 0)
+	}
+
+	query := fmt.Sprintf(`
+		SELECT at.article_id, t.name
+		FROM article_tags at
+		JOIN tags t ON t.id = at.tag_id
+		WHERE at.article_id IN (%s)
 		ORDER BY t.name
-	`
+	`, strings.Join(placeholders, ", "))
 
-	rows, err := r.db.Query(query, articleID)
+	rows, err := r.db.Query(ctx, query, args...)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var tags []string
 	for rows.Next() {
-		var tag string
-		if err := rows.Scan(&tag); err != nil {
-			return nil, err
+		var articleID, tag string
+		if err := rows.Scan(&articleID, &tag); err != nil {
+			return err
+		}
+
+		if i, ok := indexByID[articleID]; ok {
+			articles[i].TagList = append(articles[i].TagList, tag)
 		}
-		tags = append(tags, tag)
 	}
 
-	return tags, rows.Err()
+	return rows.Err()
 }
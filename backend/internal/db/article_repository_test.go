@@ -0,0 +1,670 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"fmt"
+	"io/fs"
+	"path"
+	"path/filepath"
+	"sync"
+	"sync/atomic"
+	"testing"
+
+	"github.com/mattn/go-sqlite3"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/migrations"
+)
+
+func setupArticleTestDB(t testing.TB) *sql.DB {
+	return openTestDB(t)
+}
+
+// openArticleTestDB opens a SQLite database with the full schema using the given driver
+func openArticleTestDB(t testing.TB, driverName string) *sql.DB {
+	// Use a file instead of :memory: so every pooled connection sees the same data
+	db, err := sql.Open(driverName, filepath.Join(t.TempDir(), "test.db")+"?_foreign_keys=ON")
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+
+	// Glob returns the migrations sorted by name, which is the order they apply in
+	files, err := fs.Glob(migrations.FS, "*.up.sql")
+	if err != nil || len(files) == 0 {
+		t.Fatalf("Failed to find migrations: %v", err)
+	}
+
+	for _, migration := range files {
+		schema, err := fs.ReadFile(migrations.FS, migration)
+		if err != nil {
+			t.Fatalf("Failed to read schema: %v", err)
+		}
+
+		if _, err := db.Exec(string(schema)); err != nil {
+			t.Fatalf("Failed to apply %s: %v", path.Base(migration), err)
+		}
+	}
+
+	return db
+}
+
+func createTestUser(t testing.TB, db *sql.DB, username string) *models.User {
+	user := &models.User{
+		Email:        username + "@example.com",
+		Username:     username,
+		PasswordHash: "hashedpassword",
+	}
+
+	if err := NewUserRepository(db).Create(context.Background(), user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	return user
+}
+
+func createTestArticle(t testing.TB, db *sql.DB, author *models.User, title string, tags ...string) *models.Article {
+	article := &models.Article{
+		Title:       title,
+		Description: "Description of " + title,
+		Body:        "Body of " + title,
+		TagList:     tags,
+		Author:      models.Author{Username: author.Username},
+	}
+
+	if err := NewArticleRepository(db).Create(context.Background(), article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	return article
+}
+
+func TestArticleRepository_GetFeed(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	reader := createTestUser(t, db, "reader")
+	followed := createTestUser(t, db, "followed")
+	stranger := createTestUser(t, db, "stranger")
+
+	createTestArticle(t, db, followed, "First Followed Article")
+	createTestArticle(t, db, followed, "Second Followed Article")
+	createTestArticle(t, db, stranger, "Stranger Article")
+
+	if err := NewFollowRepository(db).Follow(context.Background(), reader.ID, followed.ID); err != nil {
+		t.Fatalf("Failed to follow user: %v", err)
+	}
+
+	repo := NewArticleRepository(db)
+
+	articles, total, err := repo.GetFeed(context.Background(), reader.ID, 20, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+
+	if total != 2 || len(articles) != 2 {
+		t.Fatalf("Expected 2 feed articles, got %d (count %d)", len(articles), total)
+	}
+
+	for _, article := range articles {
+		if article.Author.Username != followed.Username {
+			t.Errorf("Expected only articles by %s, got one by %s", followed.Username, article.Author.Username)
+		}
+		if !article.Author.Following {
+			t.Error("Expected feed authors to be marked as followed")
+		}
+	}
+
+	// Pagination keeps the total count
+	articles, total, err = repo.GetFeed(context.Background(), reader.ID, 1, 1)
+	if err != nil {
+		t.Fatalf("Failed to get paginated feed: %v", err)
+	}
+
+	if total != 2 || len(articles) != 1 {
+		t.Fatalf("Expected 1 of 2 feed articles, got %d (count %d)", len(articles), total)
+	}
+}
+
+func TestArticleRepository_GetFeed_Empty(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	reader := createTestUser(t, db, "reader")
+	author := createTestUser(t, db, "author")
+	createTestArticle(t, db, author, "Unfollowed Article")
+
+	articles, total, err := NewArticleRepository(db).GetFeed(context.Background(), reader.ID, 20, 0)
+	if err != nil {
+		t.Fatalf("Failed to get feed: %v", err)
+	}
+
+	if total != 0 || articles == nil || len(articles) != 0 {
+		t.Fatalf("Expected empty non-nil feed, got %v (count %d)", articles, total)
+	}
+}
+
+func TestArticleRepository_Favorite(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	fan := createTestUser(t, db, "fan")
+	article := createTestArticle(t, db, author, "Favorite Me")
+	createTestArticle(t, db, author, "Ignore Me")
+
+	repo := NewArticleRepository(db)
+
+	// Favoriting twice counts once
+	for i := 0; i < 2; i++ {
+		if err := repo.Favorite(context.Background(), article.Slug, fan.ID); err != nil {
+			t.Fatalf("Failed to favorite article: %v", err)
+		}
+	}
+
+	got, err := repo.GetBySlug(context.Background(), article.Slug, fan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if !got.Favorited || got.FavoritesCount != 1 {
+		t.Errorf("Expected favorited article with 1 favorite, got favorited=%v count=%d", got.Favorited, got.FavoritesCount)
+	}
+
+	// Favorited is computed per viewer
+	got, err = repo.GetBySlug(context.Background(), article.Slug, author.ID)
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if got.Favorited {
+		t.Error("Expected article not to be favorited by its author")
+	}
+
+	// The favorited filter only returns the fan's favorites
+	articles, total, err := repo.GetAll(context.Background(), models.ArticleFilter{Favorited: fan.Username, Limit: 20}, "")
+	if err != nil {
+		t.Fatalf("Failed to list favorited articles: %v", err)
+	}
+	if total != 1 || len(articles) != 1 || articles[0].Slug != article.Slug {
+		t.Fatalf("Expected only %s, got %v (count %d)", article.Slug, articles, total)
+	}
+
+	if err := repo.Unfavorite(context.Background(), article.Slug, fan.ID); err != nil {
+		t.Fatalf("Failed to unfavorite article: %v", err)
+	}
+
+	got, err = repo.GetBySlug(context.Background(), article.Slug, fan.ID)
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if got.Favorited || got.FavoritesCount != 0 {
+		t.Errorf("Expected unfavorited article, got favorited=%v count=%d", got.Favorited, got.FavoritesCount)
+	}
+}
+
+func TestArticleRepository_Favorite_NotFound(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	fan := createTestUser(t, db, "fan")
+
+	if err := NewArticleRepository(db).Favorite(context.Background(), "missing-article", fan.ID); err == nil {
+		t.Fatal("Expected error for non-existent article, got nil")
+	}
+}
+
+// queryCount counts the statements prepared through the "sqlite3_counting" driver
+var queryCount atomic.Int64
+
+var registerCountingDriver sync.Once
+
+// countingDriver wraps the SQLite driver and counts every statement it runs.
+// Its connections do not expose driver.QueryerContext, so database/sql prepares
+// every query, while multi-statement schema scripts still go through ExecContext.
+type countingDriver struct {
+	driver.Driver
+}
+
+func (d countingDriver) Open(name string) (driver.Conn, error) {
+	conn, err := d.Driver.Open(name)
+	if err != nil {
+		return nil, err
+	}
+	return countingConn{conn}, nil
+}
+
+type countingConn struct {
+	driver.Conn
+}
+
+func (c countingConn) Prepare(query string) (driver.Stmt, error) {
+	queryCount.Add(1)
+	return c.Conn.Prepare(query)
+}
+
+func (c countingConn) ExecContext(ctx context.Context, query string, args []driver.NamedValue) (driver.Result, error) {
+	queryCount.Add(1)
+	return c.Conn.(driver.ExecerContext).ExecContext(ctx, query, args)
+}
+
+func setupCountingArticleDB(t testing.TB, articles int) *sql.DB {
+	registerCountingDriver.Do(func() {
+		sql.Register("sqlite3_counting", countingDriver{&sqlite3.SQLiteDriver{}})
+	})
+
+	db := openArticleTestDB(t, "sqlite3_counting")
+
+	author := createTestUser(t, db, "author")
+	fan := createTestUser(t, db, "fan")
+	repo := NewArticleRepository(db)
+	for i := 0; i < articles; i++ {
+		article := createTestArticle(t, db, author, fmt.Sprintf("Article %d", i), "go", "sql", fmt.Sprintf("tag-%d", i))
+		if err := repo.Favorite(context.Background(), article.Slug, fan.ID); err != nil {
+			t.Fatalf("Failed to favorite article: %v", err)
+		}
+	}
+
+	return db
+}
+
+func TestArticleRepository_GetAll_ConstantQueryCount(t *testing.T) {
+	db := setupCountingArticleDB(t, 25)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+
+	var counts []int64
+	for _, limit := range []int{1, 5, 20} {
+		queryCount.Store(0)
+
+		articles, total, err := repo.GetAll(context.Background(), models.ArticleFilter{Limit: limit}, "")
+		if err != nil {
+			t.Fatalf("Failed to list articles: %v", err)
+		}
+		if len(articles) != limit || total != 25 {
+			t.Fatalf("Expected %d of 25 articles, got %d (count %d)", limit, len(articles), total)
+		}
+
+		counts = append(counts, queryCount.Load())
+	}
+
+	// Count, articles and one batched tag query, whatever the page size
+	for _, count := range counts {
+		if count != 3 {
+			t.Errorf("Expected 3 queries per page, got %v", counts)
+			break
+		}
+	}
+}
+
+func TestArticleRepository_GetAll_FavoritesCountWithTags(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	fan := createTestUser(t, db, "fan")
+	article := createTestArticle(t, db, author, "Many Tags", "one", "two", "three")
+
+	repo := NewArticleRepository(db)
+	if err := repo.Favorite(context.Background(), article.Slug, fan.ID); err != nil {
+		t.Fatalf("Failed to favorite article: %v", err)
+	}
+
+	articles, _, err := repo.GetAll(context.Background(), models.ArticleFilter{Tag: "two", Limit: 20}, "")
+	if err != nil {
+		t.Fatalf("Failed to list articles: %v", err)
+	}
+
+	if len(articles) != 1 {
+		t.Fatalf("Expected 1 article, got %d", len(articles))
+	}
+	if articles[0].FavoritesCount != 1 {
+		t.Errorf("Expected 1 favorite regardless of tag count, got %d", articles[0].FavoritesCount)
+	}
+	if len(articles[0].TagList) != 3 {
+		t.Errorf("Expected all 3 tags even when filtering by one, got %v", articles[0].TagList)
+	}
+}
+
+func BenchmarkArticleRepository_GetAll(b *testing.B) {
+	db := setupCountingArticleDB(b, 50)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	filter := models.ArticleFilter{Limit: 20}
+
+	queryCount.Store(0)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, _, err := repo.GetAll(context.Background(), filter, ""); err != nil {
+			b.Fatalf("Failed to list articles: %v", err)
+		}
+	}
+	b.StopTimer()
+
+	queriesPerPage := float64(queryCount.Load()) / float64(b.N)
+	b.ReportMetric(queriesPerPage, "queries/op")
+	if queriesPerPage != 3 {
+		b.Fatalf("Expected 3 queries per page of 20 articles, got %.1f", queriesPerPage)
+	}
+}
+
+func countRows(t *testing.T, db *sql.DB, table string) int {
+	var count int
+	if err := db.QueryRow("SELECT COUNT(*) FROM " + table).Scan(&count); err != nil {
+		t.Fatalf("Failed to count %s: %v", table, err)
+	}
+	return count
+}
+
+func TestArticleRepository_Update_PartialFieldsAndTags(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	article := createTestArticle(t, db, author, "Original Title", "keep", "drop")
+
+	repo := NewArticleRepository(db)
+
+	// Only the body changes, tags are replaced
+	update := &models.Article{Body: "New body", TagList: []string{"keep", "new", "new", " "}}
+	if err := repo.Update(context.Background(), article.Slug, update); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+
+	if update.Slug != article.Slug {
+		t.Errorf("Expected slug %s to be kept, got %s", article.Slug, update.Slug)
+	}
+
+	got, err := repo.GetBySlug(context.Background(), article.Slug, "")
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+
+	if got.Title != "Original Title" || got.Description != article.Description || got.Body != "New body" {
+		t.Errorf("Expected only the body to change, got %+v", got)
+	}
+	if len(got.TagList) != 2 || got.TagList[0] != "keep" || got.TagList[1] != "new" {
+		t.Errorf("Expected tags [keep new], got %v", got.TagList)
+	}
+
+	// The replaced tag is no longer referenced anywhere
+	if count := countRows(t, db, "tags WHERE name = 'drop'"); count != 0 {
+		t.Error("Expected orphaned tag to be removed")
+	}
+
+	// A nil tag list keeps the tags, an empty one clears them
+	if err := repo.Update(context.Background(), article.Slug, &models.Article{Title: "Renamed Title"}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	got, err = repo.GetBySlug(context.Background(), "renamed-title", "")
+	if err != nil {
+		t.Fatalf("Failed to get renamed article: %v", err)
+	}
+	if len(got.TagList) != 2 {
+		t.Errorf("Expected tags to be kept, got %v", got.TagList)
+	}
+
+	if err := repo.Update(context.Background(), "renamed-title", &models.Article{TagList: []string{}}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	got, err = repo.GetBySlug(context.Background(), "renamed-title", "")
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if len(got.TagList) != 0 {
+		t.Errorf("Expected tags to be cleared, got %v", got.TagList)
+	}
+}
+
+func TestArticleRepository_Create_RollsBackOnTagFailure(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+
+	// Break tagging so the write fails after the article insert
+	if _, err := db.Exec(`DROP TABLE article_tags`); err != nil {
+		t.Fatalf("Failed to drop article_tags: %v", err)
+	}
+
+	article := &models.Article{
+		Title:       "Doomed",
+		Description: "Description",
+		Body:        "Body",
+		TagList:     []string{"go"},
+		Author:      models.Author{Username: author.Username},
+	}
+
+	if err := NewArticleRepository(db).Create(context.Background(), article); err == nil {
+		t.Fatal("Expected create to fail when tags cannot be saved")
+	}
+
+	if count := countRows(t, db, "articles"); count != 0 {
+		t.Errorf("Expected article insert to be rolled back, found %d articles", count)
+	}
+	if count := countRows(t, db, "tags"); count != 0 {
+		t.Errorf("Expected tag insert to be rolled back, found %d tags", count)
+	}
+}
+
+func TestArticleRepository_Update_RollsBackOnTagFailure(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	article := createTestArticle(t, db, author, "Stable Title")
+
+	if _, err := db.Exec(`DROP TABLE article_tags`); err != nil {
+		t.Fatalf("Failed to drop article_tags: %v", err)
+	}
+
+	update := &models.Article{Title: "Changed Title", TagList: []string{"go"}}
+	if err := NewArticleRepository(db).Update(context.Background(), article.Slug, update); err == nil {
+		t.Fatal("Expected update to fail when tags cannot be saved")
+	}
+
+	var title, slug string
+	if err := newConn(db).QueryRow(context.Background(), `SELECT title, slug FROM articles WHERE id = ?`, article.ID).Scan(&title, &slug); err != nil {
+		t.Fatalf("Failed to read article: %v", err)
+	}
+	if title != "Stable Title" || slug != article.Slug {
+		t.Errorf("Expected update to be rolled back, got title %q slug %q", title, slug)
+	}
+}
+
+func TestArticleRepository_Update_NotFound(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	if err := NewArticleRepository(db).Update(context.Background(), "missing", &models.Article{Body: "x"}); err == nil {
+		t.Fatal("Expected error for non-existent article, got nil")
+	}
+}
+
+func TestArticleRepository_Update_KeepsPreviousSlugs(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	author := createTestUser(t, db, "author")
+	reader := createTestUser(t, db, "reader")
+	article := createTestArticle(t, db, author, "Original Title")
+
+	if err := repo.Update(context.Background(), "original-title", &models.Article{Title: "Renamed Title"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if err := repo.Update(context.Background(), "renamed-title", &models.Article{Title: "Final Title"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// Every previous slug resolves to the current one
+	for _, slug := range []string{"original-title", "renamed-title", "final-title"} {
+		current, err := repo.ResolveSlug(context.Background(), slug)
+		if err != nil {
+			t.Fatalf("ResolveSlug(%q) failed: %v", slug, err)
+		}
+		if current != "final-title" {
+			t.Errorf("ResolveSlug(%q) = %q, expected %q", slug, current, "final-title")
+		}
+	}
+
+	if _, err := repo.ResolveSlug(context.Background(), "unknown-slug"); err == nil {
+		t.Error("ResolveSlug should fail for an unknown slug")
+	}
+
+	// Favorites and comments keep working through a previous slug
+	if err := repo.Favorite(context.Background(), "original-title", reader.ID); err != nil {
+		t.Fatalf("Favorite through previous slug failed: %v", err)
+	}
+
+	commentRepo := NewCommentRepository(db)
+	if err := commentRepo.Create(context.Background(), &models.Comment{Body: "Still here"}, "renamed-title", reader.ID); err != nil {
+		t.Fatalf("Comment through previous slug failed: %v", err)
+	}
+
+	comments, err := commentRepo.GetByArticleSlug(context.Background(), "original-title", "")
+	if err != nil {
+		t.Fatalf("GetByArticleSlug through previous slug failed: %v", err)
+	}
+	if len(comments) != 1 {
+		t.Errorf("Expected 1 comment through previous slug, got %d", len(comments))
+	}
+
+	got, err := repo.GetBySlug(context.Background(), "final-title", reader.ID)
+	if err != nil {
+		t.Fatalf("GetBySlug failed: %v", err)
+	}
+	if got.ID != article.ID || !got.Favorited || got.FavoritesCount != 1 {
+		t.Errorf("Expected the renamed article favorited once, got id %s favorited %v count %d",
+			got.ID, got.Favorited, got.FavoritesCount)
+	}
+}
+
+// countSlugHistory returns the number of previous slugs recorded for an article
+func countSlugHistory(t *testing.T, db *sql.DB, articleID string) int {
+	var count int
+	if err := db.QueryRow(`SELECT COUNT(*) FROM article_slug_history WHERE article_id = ?`, articleID).Scan(&count); err != nil {
+		t.Fatalf("Failed to count slug history: %v", err)
+	}
+	return count
+}
+
+func TestArticleRepository_Update_SameTitleKeepsSlug(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	author := createTestUser(t, db, "author")
+	createTestArticle(t, db, author, "Foo")
+	article := createTestArticle(t, db, author, "Foo")
+	if article.Slug != "foo-1" {
+		t.Fatalf("Expected the second article to get foo-1, got %q", article.Slug)
+	}
+
+	// Edit forms send the unchanged title with every save
+	for _, title := range []string{"Foo", " Foo ", "Foo"} {
+		update := &models.Article{Title: title, Body: "Edited"}
+		if err := repo.Update(context.Background(), "foo-1", update); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if update.Slug != "foo-1" {
+			t.Fatalf("Expected the slug to stay foo-1, got %q", update.Slug)
+		}
+	}
+
+	if count := countSlugHistory(t, db, article.ID); count != 0 {
+		t.Errorf("Expected no slug history, got %d rows", count)
+	}
+}
+
+func TestArticleRepository_Update_ReservedTitleKeepsSlug(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	author := createTestUser(t, db, "author")
+	article := createTestArticle(t, db, author, "Feed")
+	if article.Slug != "feed-1" {
+		t.Fatalf("Expected feed-1 since feed is reserved, got %q", article.Slug)
+	}
+
+	// Neither the same title nor another one slugged to feed moves the slug
+	for _, title := range []string{"Feed", "FEED!", "Feed"} {
+		update := &models.Article{Title: title}
+		if err := repo.Update(context.Background(), "feed-1", update); err != nil {
+			t.Fatalf("Update failed: %v", err)
+		}
+		if update.Slug != "feed-1" {
+			t.Fatalf("Expected the slug to stay feed-1 for title %q, got %q", title, update.Slug)
+		}
+	}
+
+	if count := countSlugHistory(t, db, article.ID); count != 0 {
+		t.Errorf("Expected no slug history, got %d rows", count)
+	}
+}
+
+func TestArticleRepository_Update_RenameBackReclaimsSlug(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	author := createTestUser(t, db, "author")
+	article := createTestArticle(t, db, author, "Foo")
+
+	if err := repo.Update(context.Background(), "foo", &models.Article{Title: "Bar"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// The article's own previous slug does not count as taken
+	update := &models.Article{Title: "Foo"}
+	if err := repo.Update(context.Background(), "bar", update); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+	if update.Slug != "foo" {
+		t.Errorf("Expected the article to take back foo, got %q", update.Slug)
+	}
+
+	current, err := repo.ResolveSlug(context.Background(), "bar")
+	if err != nil || current != "foo" {
+		t.Errorf("ResolveSlug(bar) = %q, %v, expected foo", current, err)
+	}
+	if count := countSlugHistory(t, db, article.ID); count != 1 {
+		t.Errorf("Expected only bar in the slug history, got %d rows", count)
+	}
+}
+
+func TestArticleRepository_Create_AvoidsPreviousSlugs(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	repo := NewArticleRepository(db)
+	author := createTestUser(t, db, "author")
+	createTestArticle(t, db, author, "Shared Title")
+
+	if err := repo.Update(context.Background(), "shared-title", &models.Article{Title: "Another Title"}); err != nil {
+		t.Fatalf("Update failed: %v", err)
+	}
+
+	// A new article must not take a slug that still points at the renamed article
+	article := createTestArticle(t, db, author, "Shared Title")
+	if article.Slug != "shared-title-1" {
+		t.Errorf("Expected slug %q, got %q", "shared-title-1", article.Slug)
+	}
+
+	current, err := repo.ResolveSlug(context.Background(), "shared-title")
+	if err != nil {
+		t.Fatalf("ResolveSlug failed: %v", err)
+	}
+	if current != "another-title" {
+		t.Errorf("Expected previous slug to resolve to %q, got %q", "another-title", current)
+	}
+
+	// Deleting the article removes its slug history
+	if err := repo.Delete(context.Background(), "another-title"); err != nil {
+		t.Fatalf("Delete failed: %v", err)
+	}
+	if _, err := repo.ResolveSlug(context.Background(), "shared-title"); err == nil {
+		t.Error("Expected previous slug of a deleted article to stop resolving")
+	}
+}
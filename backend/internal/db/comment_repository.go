@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -10,18 +11,21 @@ import (
 
 // CommentRepository handles comment data operations
 type CommentRepository struct {
-	db *sql.DB
+	db *conn
 }
 
 // NewCommentRepository creates a new comment repository
 func NewCommentRepository(db *sql.DB) *CommentRepository {
-	return &CommentRepository{db: db}
+	return &CommentRepository{db: newConn(db)}
 }
 
 // Create creates a new comment
-func (r *CommentRepository) Create(comment *models.Comment, articleSlug, authorID string) error {
+func (r *CommentRepository) Create(ctx context.Context, comment *models.Comment, articleSlug, authorID string) error {
+	ctx, span := startSpan(ctx, "CommentRepository.Create")
+	defer span.End()
+
 	// First, get the article ID from slug
-	articleID, err := r.getArticleIDBySlug(articleSlug)
+	articleID, err := r.getArticleIDBySlug(ctx, articleSlug)
 	if err != nil {
 		return fmt.Errorf("failed to get article ID: %w", err)
 	}
@@ -35,7 +39,7 @@ func (r *CommentRepository) Create(comment *models.Comment, articleSlug, authorI
 		RETURNING id
 	`
 
-	row := r.db.QueryRow(query, comment.Body, authorID, articleID,
+	row := r.db.QueryRow(ctx, query, comment.Body, authorID, articleID,
 		comment.CreatedAt, comment.UpdatedAt)
 
 	err = row.Scan(&comment.ID)
@@ -46,19 +50,23 @@ func (r *CommentRepository) Create(comment *models.Comment, articleSlug, authorI
 	return nil
 }
 
-// GetByArticleSlug retrieves all comments for an article
-func (r *CommentRepository) GetByArticleSlug(articleSlug string) ([]models.Comment, error) {
+// GetByArticleSlug retrieves all comments for an article.
+// viewerID is the authenticated user the comments are rendered for and may be empty.
+func (r *CommentRepository) GetByArticleSlug(ctx context.Context, articleSlug, viewerID string) ([]models.Comment, error) {
+	ctx, span := startSpan(ctx, "CommentRepository.GetByArticleSlug")
+	defer span.End()
+
 	query := `
 		SELECT c.id, c.body, c.created_at, c.updated_at,
-		       u.username, u.bio, u.image
+		       u.username, u.bio, u.image,
+		       EXISTS(SELECT 1 FROM follows fo WHERE fo.follower_id = ? AND fo.following_id = u.id) as following
 		FROM comments c
 		JOIN users u ON c.author_id = u.id
-		JOIN articles a ON c.article_id = a.id
-		WHERE a.slug = ?
+		WHERE c.article_id = (` + articleIDBySlugQuery + `)
 		ORDER BY c.created_at ASC
 	`
 
-	rows, err := r.db.Query(query, articleSlug)
+	rows, err := r.db.Query(ctx, query, viewerID, articleSlug, articleSlug)
 	if err != nil {
 		return nil, fmt.Errorf("failed to query comments: %w", err)
 	}
@@ -69,6 +77,7 @@ func (r *CommentRepository) GetByArticleSlug(articleSlug string) ([]models.Comme
 	for rows.Next() {
 		var comment models.Comment
 		var author models.User
+		var following bool
 
 		err := rows.Scan(
 			&comment.ID,
@@ -78,6 +87,7 @@ func (r *CommentRepository) GetByArticleSlug(articleSlug string) ([]models.Comme
 			&author.Username,
 			&author.Bio,
 			&author.Image,
+			&following,
 		)
 		if err != nil {
 			return nil, fmt.Errorf("failed to scan comment: %w", err)
@@ -88,7 +98,7 @@ func (r *CommentRepository) GetByArticleSlug(articleSlug string) ([]models.Comme
 			Username:  author.Username,
 			Bio:       author.Bio,
 			Image:     author.Image,
-			Following: false, // TODO: Implement following logic
+			Following: following,
 		}
 
 		comments = append(comments, comment)
@@ -102,7 +112,10 @@ func (r *CommentRepository) GetByArticleSlug(articleSlug string) ([]models.Comme
 }
 
 // GetByID retrieves a comment by its ID
-func (r *CommentRepository) GetByID(commentID string) (*models.Comment, error) {
+func (r *CommentRepository) GetByID(ctx context.Context, commentID string) (*models.Comment, error) {
+	ctx, span := startSpan(ctx, "CommentRepository.GetByID")
+	defer span.End()
+
 	query := `
 		SELECT c.id, c.body, c.created_at, c.updated_at,
 		       u.username, u.bio, u.image
@@ -113,7 +126,7 @@ func (r *CommentRepository) GetByID(commentID string) (*models.Comment, error) {
 
 	var comment models.Comment
 	var author models.User
-	row := r.db.QueryRow(query, commentID)
+	row := r.db.QueryRow(ctx, query, commentID)
 
 	err := row.Scan(
 		&comment.ID,
@@ -127,7 +140,7 @@ func (r *CommentRepository) GetByID(commentID string) (*models.Comment, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("comment not found")
+			return nil, notFoundError("comment")
 		}
 		return nil, fmt.Errorf("failed to get comment by ID: %w", err)
 	}
@@ -137,17 +150,21 @@ func (r *CommentRepository) GetByID(commentID string) (*models.Comment, error) {
 		Username:  author.Username,
 		Bio:       author.Bio,
 		Image:     author.Image,
-		Following: false, // TODO: Implement following logic
+		Following: false,
 	}
 
 	return &comment, nil
 }
 
-// Delete deletes a comment by ID
-func (r *CommentRepository) Delete(commentID string) error {
-	query := `DELETE FROM comments WHERE id = ?`
+// Delete deletes a comment written by the given author.
+// It returns ErrForbidden when the comment belongs to another user.
+func (r *CommentRepository) Delete(ctx context.Context, commentID, authorID string) error {
+	ctx, span := startSpan(ctx, "CommentRepository.Delete")
+	defer span.End()
+
+	query := `DELETE FROM comments WHERE id = ? AND author_id = ?`
 
-	result, err := r.db.Exec(query, commentID)
+	result, err := r.db.Exec(ctx, query, commentID, authorID)
 	if err != nil {
 		return fmt.Errorf("failed to delete comment: %w", err)
 	}
@@ -158,18 +175,30 @@ func (r *CommentRepository) Delete(commentID string) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("comment not found")
+		// Tell a missing comment apart from one written by someone else
+		var exists bool
+		if err := r.db.QueryRow(ctx, `SELECT EXISTS(SELECT 1 FROM comments WHERE id = ?)`, commentID).Scan(&exists); err != nil {
+			return fmt.Errorf("failed to check comment: %w", err)
+		}
+
+		if exists {
+			return forbiddenError("comment")
+		}
+		return notFoundError("comment")
 	}
 
 	return nil
 }
 
 // IsAuthor checks if a user is the author of a comment
-func (r *CommentRepository) IsAuthor(commentID, userID string) (bool, error) {
+func (r *CommentRepository) IsAuthor(ctx context.Context, commentID, userID string) (bool, error) {
+	ctx, span := startSpan(ctx, "CommentRepository.IsAuthor")
+	defer span.End()
+
 	query := `SELECT COUNT(*) FROM comments WHERE id = ? AND author_id = ?`
 
 	var count int
-	err := r.db.QueryRow(query, commentID, userID).Scan(&count)
+	err := r.db.QueryRow(ctx, query, commentID, userID).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check comment authorship: %w", err)
 	}
@@ -179,15 +208,14 @@ func (r *CommentRepository) IsAuthor(commentID, userID string) (bool, error) {
 
 // Helper methods
 
-// getArticleIDBySlug gets an article ID by its slug
-func (r *CommentRepository) getArticleIDBySlug(slug string) (string, error) {
+// getArticleIDBySlug gets an article ID by its current or previous slug
+func (r *CommentRepository) getArticleIDBySlug(ctx context.Context, slug string) (string, error) {
 	var articleID string
-	query := `SELECT id FROM articles WHERE slug = ?`
 
-	err := r.db.QueryRow(query, slug).Scan(&articleID)
+	err := r.db.QueryRow(ctx, articleIDBySlugQuery, slug, slug).Scan(&articleID)
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return "", fmt.Errorf("article not found")
+			return "", notFoundError("article")
 		}
 		return "", fmt.Errorf("failed to get article ID: %w", err)
 	}
@@ -0,0 +1,36 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func TestCommentRepository_Delete(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	other := createTestUser(t, db, "other")
+	createTestArticle(t, db, author, "Commented Article")
+
+	repo := NewCommentRepository(db)
+	comment := &models.Comment{Body: "A comment"}
+	if err := repo.Create(context.Background(), comment, "commented-article", author.ID); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	if err := repo.Delete(context.Background(), comment.ID, other.ID); !errors.Is(err, ErrForbidden) {
+		t.Errorf("Expected ErrForbidden deleting another user's comment, got %v", err)
+	}
+
+	if err := repo.Delete(context.Background(), comment.ID, author.ID); err != nil {
+		t.Fatalf("Failed to delete comment: %v", err)
+	}
+
+	if err := repo.Delete(context.Background(), comment.ID, author.ID); !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound deleting a deleted comment, got %v", err)
+	}
+}
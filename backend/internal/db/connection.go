@@ -1,24 +1,44 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 
+	_ "github.com/lib/pq"
 	_ "github.com/mattn/go-sqlite3"
 )
 
 // DB represents the database connection
 type DB struct {
 	*sql.DB
+	Dialect Dialect
 }
 
-// NewConnection creates a new database connection
-func NewConnection() (*DB, error) {
-	dbPath := os.Getenv("DATABASE_URL")
-	if dbPath == "" {
-		dbPath = "./data/conduit.db"
+// dbtx is implemented by both conn and connTx so helpers can run inside or outside a transaction
+type dbtx interface {
+	Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	Query(ctx context.Context, query string, args ...interface{}) (*timedRows, error)
+	QueryRow(ctx context.Context, query string, args ...interface{}) *timedRow
+}
+
+// DialectFromURL returns Postgres for postgres:// and postgresql:// URLs and SQLite
+// for anything else, which is treated as the path of a database file
+func DialectFromURL(databaseURL string) Dialect {
+	if strings.HasPrefix(databaseURL, "postgres://") || strings.HasPrefix(databaseURL, "postgresql://") {
+		return Postgres
+	}
+	return SQLite
+}
+
+// NewConnection creates a new database connection.
+// The URL picks the database, see DialectFromURL.
+func NewConnection(dbPath string) (*DB, error) {
+	if DialectFromURL(dbPath) == Postgres {
+		return newPostgresConnection(dbPath)
 	}
 
 	// EFS 경로인 경우 디렉토리 생성 확인
@@ -46,7 +66,22 @@ func NewConnection() (*DB, error) {
 		return nil, fmt.Errorf("failed to optimize SQLite: %w", err)
 	}
 
-	return &DB{db}, nil
+	return &DB{DB: db, Dialect: SQLite}, nil
+}
+
+// newPostgresConnection connects to the PostgreSQL database at the given URL
+func newPostgresConnection(databaseURL string) (*DB, error) {
+	db, err := sql.Open("postgres", databaseURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open database: %w", err)
+	}
+
+	if err := db.Ping(); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+
+	return &DB{DB: db, Dialect: Postgres}, nil
 }
 
 // optimizeSQLite applies SQLite performance optimizations
@@ -66,7 +101,16 @@ func optimizeSQLite(db *sql.DB) error {
 	return nil
 }
 
-// Close closes the database connection
+// Close closes the database connection. For SQLite it first checkpoints the
+// write-ahead log into the database file and truncates it, so the file is
+// complete on its own once the process exits.
 func (db *DB) Close() error {
+	if db.Dialect == SQLite {
+		if _, err := db.DB.Exec("PRAGMA wal_checkpoint(TRUNCATE);"); err != nil {
+			db.DB.Close()
+			return fmt.Errorf("failed to checkpoint WAL: %w", err)
+		}
+	}
+
 	return db.DB.Close()
 }
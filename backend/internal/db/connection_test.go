@@ -0,0 +1,36 @@
+package db
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestDB_CloseCheckpointsWAL(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "conduit.db")
+
+	database, err := NewConnection(path)
+	if err != nil {
+		t.Fatalf("Failed to connect: %v", err)
+	}
+
+	if _, err := database.Exec(`CREATE TABLE notes (body TEXT)`); err != nil {
+		t.Fatalf("Failed to create table: %v", err)
+	}
+	if _, err := database.Exec(`INSERT INTO notes (body) VALUES ('hello')`); err != nil {
+		t.Fatalf("Failed to insert: %v", err)
+	}
+
+	// The write is in the WAL until it is checkpointed
+	if info, err := os.Stat(path + "-wal"); err != nil || info.Size() == 0 {
+		t.Fatalf("Expected the write in the WAL, got %v", err)
+	}
+
+	if err := database.Close(); err != nil {
+		t.Fatalf("Failed to close: %v", err)
+	}
+
+	if info, err := os.Stat(path + "-wal"); err == nil && info.Size() > 0 {
+		t.Errorf("Expected the WAL to be checkpointed on close, %d bytes left", info.Size())
+	}
+}
@@ -0,0 +1,163 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+
+	"github.com/lib/pq"
+)
+
+// Dialect identifies the SQL dialect spoken by a database
+type Dialect string
+
+const (
+	// SQLite is the default dialect, used for file databases
+	SQLite Dialect = "sqlite"
+	// Postgres is used when DATABASE_URL is a postgres:// URL
+	Postgres Dialect = "postgres"
+)
+
+// dialectOf reports the dialect of the driver behind db
+func dialectOf(db *sql.DB) Dialect {
+	if _, ok := db.Driver().(*pq.Driver); ok {
+		return Postgres
+	}
+	return SQLite
+}
+
+// Rebind rewrites the ? placeholders of a query into the dialect's placeholders.
+// Question marks inside string literals are left alone.
+func (d Dialect) Rebind(query string) string {
+	if d != Postgres || !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	b.Grow(len(query) + 8)
+
+	n := 0
+	inString := false
+	for i := 0; i < len(query); i++ {
+		c := query[i]
+		switch {
+		case c == '\'':
+			inString = !inString
+		case c == '?' && !inString:
+			n++
+			b.WriteByte('$')
+			b.WriteString(strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(c)
+	}
+
+	return b.String()
+}
+
+// caseInsensitiveLike returns the operator matching LIKE patterns without regard
+// to ASCII case, which plain LIKE already does in SQLite
+func (d Dialect) caseInsensitiveLike() string {
+	if d == Postgres {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// conn runs queries written with ? placeholders on a database of any dialect.
+// Every statement is traced, see startQuerySpan.
+type conn struct {
+	*sql.DB
+	dialect Dialect
+}
+
+func newConn(db *sql.DB) *conn {
+	return &conn{DB: db, dialect: dialectOf(db)}
+}
+
+func (c *conn) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return execContext(ctx, c.DB, c.dialect, query, args)
+}
+
+func (c *conn) Query(ctx context.Context, query string, args ...interface{}) (*timedRows, error) {
+	return queryContext(ctx, c.DB, c.dialect, query, args)
+}
+
+func (c *conn) QueryRow(ctx context.Context, query string, args ...interface{}) *timedRow {
+	return queryRowContext(ctx, c.DB, c.dialect, query, args)
+}
+
+// Begin starts a transaction that rebinds its queries like c. The transaction
+// is rolled back if ctx is canceled, its statements are timed individually.
+func (c *conn) Begin(ctx context.Context) (*connTx, error) {
+	sqlTx, err := c.DB.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &connTx{Tx: sqlTx, dialect: c.dialect}, nil
+}
+
+// connTx is a transaction started by conn.Begin
+type connTx struct {
+	*sql.Tx
+	dialect Dialect
+}
+
+func (t *connTx) Exec(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return execContext(ctx, t.Tx, t.dialect, query, args)
+}
+
+func (t *connTx) Query(ctx context.Context, query string, args ...interface{}) (*timedRows, error) {
+	return queryContext(ctx, t.Tx, t.dialect, query, args)
+}
+
+func (t *connTx) QueryRow(ctx context.Context, query string, args ...interface{}) *timedRow {
+	return queryRowContext(ctx, t.Tx, t.dialect, query, args)
+}
+
+// sqlQueryer is implemented by both *sql.DB and *sql.Tx
+type sqlQueryer interface {
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+func execContext(ctx context.Context, q sqlQueryer, d Dialect, query string, args []interface{}) (sql.Result, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+	defer cancel()
+
+	ctx, span := d.startQuerySpan(ctx, query)
+	result, err := q.ExecContext(ctx, d.Rebind(query), args...)
+	err = queryError(ctx, err)
+	endQuerySpan(span, err)
+	return result, err
+}
+
+// queryContext ends the span once the query has run, reading the rows is not
+// included. The timeout covers reading the rows and ends when they are closed.
+func queryContext(ctx context.Context, q sqlQueryer, d Dialect, query string, args []interface{}) (*timedRows, error) {
+	ctx, cancel := withQueryTimeout(ctx)
+
+	ctx, span := d.startQuerySpan(ctx, query)
+	rows, err := q.QueryContext(ctx, d.Rebind(query), args...)
+	err = queryError(ctx, err)
+	endQuerySpan(span, err)
+	if err != nil {
+		cancel()
+		return nil, err
+	}
+
+	return &timedRows{Rows: rows, ctx: ctx, cancel: cancel}, nil
+}
+
+// queryRowContext returns a row whose timeout ends once it has been scanned
+func queryRowContext(ctx context.Context, q sqlQueryer, d Dialect, query string, args []interface{}) *timedRow {
+	ctx, cancel := withQueryTimeout(ctx)
+
+	ctx, span := d.startQuerySpan(ctx, query)
+	row := q.QueryRowContext(ctx, d.Rebind(query), args...)
+	endQuerySpan(span, queryError(ctx, row.Err()))
+
+	return &timedRow{Row: row, ctx: ctx, cancel: cancel}
+}
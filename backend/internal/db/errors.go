@@ -0,0 +1,76 @@
+package db
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/lib/pq"
+	"github.com/mattn/go-sqlite3"
+)
+
+// Sentinel errors returned by the repositories, compare them with errors.Is
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrConflict  = errors.New("already exists")
+	ErrForbidden = errors.New("forbidden")
+	// ErrTimeout is returned when a statement runs longer than the query timeout,
+	// e.g. while waiting for a locked SQLite file
+	ErrTimeout = errors.New("query timed out")
+)
+
+// Error is a repository error about a specific resource, such as an "article"
+// that does not exist or an "email" that is already taken.
+// errors.Is matches it against its Kind.
+type Error struct {
+	Kind     error
+	Resource string
+}
+
+// Error returns a message such as "article not found"
+func (e *Error) Error() string {
+	return e.Resource + " " + e.Kind.Error()
+}
+
+// Unwrap returns the sentinel error
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
+
+// notFoundError reports that the resource does not exist
+func notFoundError(resource string) error {
+	return &Error{Kind: ErrNotFound, Resource: resource}
+}
+
+// forbiddenError reports that the resource belongs to another user
+func forbiddenError(resource string) error {
+	return &Error{Kind: ErrForbidden, Resource: resource}
+}
+
+// uniqueViolation converts a UNIQUE constraint failure into an ErrConflict on the
+// violated column. Other errors are returned unchanged.
+func uniqueViolation(err error) error {
+	var sqliteErr sqlite3.Error
+	if errors.As(err, &sqliteErr) && sqliteErr.ExtendedCode == sqlite3.ErrConstraintUnique {
+		// The message names the column, e.g. "UNIQUE constraint failed: users.email"
+		resource := sqliteErr.Error()
+		if i := strings.LastIndex(resource, "."); i >= 0 {
+			resource = resource[i+1:]
+		}
+		return &Error{Kind: ErrConflict, Resource: resource}
+	}
+
+	var pqErr *pq.Error
+	if errors.As(err, &pqErr) && pqErr.Code == pgUniqueViolation {
+		// The detail names the column, e.g. "Key (email)=(a@example.com) already exists."
+		resource := pqErr.Constraint
+		if start, end := strings.Index(pqErr.Detail, "("), strings.Index(pqErr.Detail, ")"); start >= 0 && end > start {
+			resource = pqErr.Detail[start+1 : end]
+		}
+		return &Error{Kind: ErrConflict, Resource: resource}
+	}
+
+	return err
+}
+
+// pgUniqueViolation is the PostgreSQL error code of a UNIQUE constraint failure
+const pgUniqueViolation = "23505"
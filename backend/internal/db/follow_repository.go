@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+)
+
+// FollowRepository handles follow relationship data operations
+type FollowRepository struct {
+	db *conn
+}
+
+// NewFollowRepository creates a new follow repository
+func NewFollowRepository(db *sql.DB) *FollowRepository {
+	return &FollowRepository{db: newConn(db)}
+}
+
+// Follow makes followerID follow followingID. Following an already followed user is a no-op.
+func (r *FollowRepository) Follow(ctx context.Context, followerID, followingID string) error {
+	ctx, span := startSpan(ctx, "FollowRepository.Follow")
+	defer span.End()
+
+	query := `
+		INSERT INTO follows (follower_id, following_id, created_at)
+		VALUES (?, ?, ?)
+		ON CONFLICT (follower_id, following_id) DO NOTHING
+	`
+
+	if _, err := r.db.Exec(ctx, query, followerID, followingID, time.Now()); err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+// Unfollow removes the follow relationship. Unfollowing a user that is not followed is a no-op.
+func (r *FollowRepository) Unfollow(ctx context.Context, followerID, followingID string) error {
+	ctx, span := startSpan(ctx, "FollowRepository.Unfollow")
+	defer span.End()
+
+	query := `DELETE FROM follows WHERE follower_id = ? AND following_id = ?`
+
+	if _, err := r.db.Exec(ctx, query, followerID, followingID); err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+// IsFollowing checks if followerID follows followingID
+func (r *FollowRepository) IsFollowing(ctx context.Context, followerID, followingID string) (bool, error) {
+	ctx, span := startSpan(ctx, "FollowRepository.IsFollowing")
+	defer span.End()
+
+	// Anonymous viewers never follow anyone
+	if followerID == "" {
+		return false, nil
+	}
+
+	query := `SELECT COUNT(*) FROM follows WHERE follower_id = ? AND following_id = ?`
+
+	var count int
+	err := r.db.QueryRow(ctx, query, followerID, followingID).Scan(&count)
+	if err != nil {
+		return false, fmt.Errorf("failed to check follow status: %w", err)
+	}
+
+	return count > 0, nil
+}
@@ -0,0 +1,84 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func setupFollowTestDB(t *testing.T) (*sql.DB, *models.User, *models.User) {
+	db := setupTestDB(t)
+
+	userRepo := NewUserRepository(db)
+	follower := &models.User{Email: "follower@example.com", Username: "follower", PasswordHash: "hash"}
+	followed := &models.User{Email: "followed@example.com", Username: "followed", PasswordHash: "hash"}
+	for _, user := range []*models.User{follower, followed} {
+		if err := userRepo.Create(context.Background(), user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	return db, follower, followed
+}
+
+func TestFollowRepository_FollowAndUnfollow(t *testing.T) {
+	db, follower, followed := setupFollowTestDB(t)
+	defer db.Close()
+
+	repo := NewFollowRepository(db)
+
+	if err := repo.Follow(context.Background(), follower.ID, followed.ID); err != nil {
+		t.Fatalf("Failed to follow user: %v", err)
+	}
+
+	// Following twice must not fail
+	if err := repo.Follow(context.Background(), follower.ID, followed.ID); err != nil {
+		t.Fatalf("Failed to follow user twice: %v", err)
+	}
+
+	following, err := repo.IsFollowing(context.Background(), follower.ID, followed.ID)
+	if err != nil {
+		t.Fatalf("Failed to check follow status: %v", err)
+	}
+	if !following {
+		t.Fatal("Expected follower to follow followed user")
+	}
+
+	// Follow relationships are directional
+	following, err = repo.IsFollowing(context.Background(), followed.ID, follower.ID)
+	if err != nil {
+		t.Fatalf("Failed to check follow status: %v", err)
+	}
+	if following {
+		t.Fatal("Expected follow relationship to be one-way")
+	}
+
+	if err := repo.Unfollow(context.Background(), follower.ID, followed.ID); err != nil {
+		t.Fatalf("Failed to unfollow user: %v", err)
+	}
+
+	following, err = repo.IsFollowing(context.Background(), follower.ID, followed.ID)
+	if err != nil {
+		t.Fatalf("Failed to check follow status: %v", err)
+	}
+	if following {
+		t.Fatal("Expected follow relationship to be removed")
+	}
+}
+
+func TestFollowRepository_IsFollowing_Anonymous(t *testing.T) {
+	db, _, followed := setupFollowTestDB(t)
+	defer db.Close()
+
+	repo := NewFollowRepository(db)
+
+	following, err := repo.IsFollowing(context.Background(), "", followed.ID)
+	if err != nil {
+		t.Fatalf("Failed to check follow status: %v", err)
+	}
+	if following {
+		t.Fatal("Expected anonymous viewer to follow nobody")
+	}
+}
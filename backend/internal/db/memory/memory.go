@@ -0,0 +1,674 @@
+// Package memory implements the db stores in process memory. It backs handler
+// tests and the -storage=memory development mode, and loses all data on exit.
+package memory
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/utils"
+)
+
+// Store holds every table in memory. Its stores share the same data, so an
+// article created through Articles can be favorited by a user created through Users.
+type Store struct {
+	mu sync.RWMutex
+
+	users     map[string]*models.User // by ID
+	articles  map[string]*article     // by ID
+	slugs     map[string]string       // previous slug to article ID
+	comments  map[string]*comment     // by ID
+	follows   map[follow]bool
+	favorites map[favorite]bool
+
+	// seq orders rows created within the same clock tick
+	seq int64
+}
+
+type article struct {
+	models.Article
+	authorID string
+	seq      int64
+}
+
+type comment struct {
+	models.Comment
+	articleID string
+	authorID  string
+	seq       int64
+}
+
+type follow struct {
+	followerID  string
+	followingID string
+}
+
+type favorite struct {
+	userID    string
+	articleID string
+}
+
+// New creates an empty store
+func New() *Store {
+	return &Store{
+		users:     make(map[string]*models.User),
+		articles:  make(map[string]*article),
+		slugs:     make(map[string]string),
+		comments:  make(map[string]*comment),
+		follows:   make(map[follow]bool),
+		favorites: make(map[favorite]bool),
+	}
+}
+
+// Users returns the user store
+func (s *Store) Users() db.UserStore { return userStore{s} }
+
+// Articles returns the article store
+func (s *Store) Articles() db.ArticleStore { return articleStore{s} }
+
+// Comments returns the comment store
+func (s *Store) Comments() db.CommentStore { return commentStore{s} }
+
+// Follows returns the follow store
+func (s *Store) Follows() db.FollowStore { return followStore{s} }
+
+// Tags returns the tag store
+func (s *Store) Tags() db.TagStore { return tagStore{s} }
+
+// newID returns a random hex ID like the ones SQLite generates
+func newID() string {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		panic(fmt.Sprintf("failed to generate ID: %v", err))
+	}
+	return hex.EncodeToString(b)
+}
+
+func notFound(resource string) error {
+	return &db.Error{Kind: db.ErrNotFound, Resource: resource}
+}
+
+func conflict(resource string) error {
+	return &db.Error{Kind: db.ErrConflict, Resource: resource}
+}
+
+// nextSeq must be called with the write lock held
+func (s *Store) nextSeq() int64 {
+	s.seq++
+	return s.seq
+}
+
+// Users
+
+type userStore struct{ *Store }
+
+func (s userStore) Create(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkUnique(user); err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	user.ID = newID()
+	user.CreatedAt = time.Now()
+	user.UpdatedAt = user.CreatedAt
+
+	stored := *user
+	s.users[user.ID] = &stored
+	return nil
+}
+
+func (s userStore) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	return s.findUser(func(u *models.User) bool { return u.Email == email })
+}
+
+func (s userStore) GetByID(ctx context.Context, id string) (*models.User, error) {
+	return s.findUser(func(u *models.User) bool { return u.ID == id })
+}
+
+func (s userStore) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	return s.findUser(func(u *models.User) bool { return u.Username == username })
+}
+
+func (s userStore) EmailExists(ctx context.Context, email string) (bool, error) {
+	_, err := s.GetByEmail(ctx, email)
+	return err == nil, nil
+}
+
+func (s userStore) UsernameExists(ctx context.Context, username string) (bool, error) {
+	_, err := s.GetByUsername(ctx, username)
+	return err == nil, nil
+}
+
+func (s userStore) Update(ctx context.Context, user *models.User) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if err := s.checkUnique(user); err != nil {
+		return fmt.Errorf("failed to update user: %w", err)
+	}
+
+	// Updating a missing user is a no-op, like an UPDATE matching no rows
+	stored, ok := s.users[user.ID]
+	if !ok {
+		return nil
+	}
+
+	user.UpdatedAt = time.Now()
+	stored.Email = user.Email
+	stored.Username = user.Username
+	stored.PasswordHash = user.PasswordHash
+	stored.Bio = user.Bio
+	stored.Image = user.Image
+	stored.UpdatedAt = user.UpdatedAt
+	return nil
+}
+
+// checkUnique reports a conflict when another user has the same email or username.
+// It must be called with the lock held.
+func (s userStore) checkUnique(user *models.User) error {
+	for _, other := range s.users {
+		if other.ID == user.ID {
+			continue
+		}
+		if other.Email == user.Email {
+			return conflict("email")
+		}
+		if other.Username == user.Username {
+			return conflict("username")
+		}
+	}
+	return nil
+}
+
+func (s userStore) findUser(match func(*models.User) bool) (*models.User, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	for _, user := range s.users {
+		if match(user) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, notFound("user")
+}
+
+// Articles
+
+type articleStore struct{ *Store }
+
+func (s articleStore) Create(ctx context.Context, a *models.Article) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	authorID, ok := s.userIDByUsername(a.Author.Username)
+	if !ok {
+		return fmt.Errorf("failed to get author ID: %w", notFound("user"))
+	}
+
+	a.ID = newID()
+	a.Slug = utils.GenerateUniqueSlug(a.Title, s.similarSlugs(utils.GenerateSlug(a.Title), ""))
+	a.CreatedAt = time.Now()
+	a.UpdatedAt = a.CreatedAt
+	if len(a.TagList) > 0 {
+		a.TagList = normalizeTags(a.TagList)
+	}
+
+	stored := &article{Article: *a, authorID: authorID, seq: s.nextSeq()}
+	stored.TagList = append([]string{}, a.TagList...)
+	s.articles[a.ID] = stored
+	return nil
+}
+
+func (s articleStore) GetBySlug(ctx context.Context, slug, viewerID string) (*models.Article, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	a, ok := s.articleBySlug(slug)
+	if !ok {
+		return nil, notFound("article")
+	}
+
+	view := s.view(a, viewerID)
+	return &view, nil
+}
+
+func (s articleStore) GetAll(ctx context.Context, filter models.ArticleFilter, viewerID string) ([]models.Article, int, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	favoritedBy, _ := s.userIDByUsername(filter.Favorited)
+
+	matches := make([]*article, 0)
+	for _, a := range s.articles {
+		if filter.Tag != "" && !containsTag(a.TagList, filter.Tag) {
+			continue
+		}
+		if filter.Author != "" && s.author(a.authorID, "").Username != filter.Author {
+			continue
+		}
+		if filter.Favorited != "" && !s.favorites[favorite{favoritedBy, a.ID}] {
+			continue
+		}
+		if filter.FollowedBy != "" && !s.follows[follow{filter.FollowedBy, a.authorID}] {
+			continue
+		}
+		matches = append(matches, a)
+	}
+
+	// Most recent first
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.After(matches[j].CreatedAt)
+		}
+		return matches[i].seq > matches[j].seq
+	})
+
+	total := len(matches)
+	start := min(max(filter.Offset, 0), total)
+	end := total
+	if filter.Limit >= 0 {
+		end = min(start+filter.Limit, total)
+	}
+
+	// Initialize with empty slice to ensure JSON serializes as [] instead of null
+	articles := make([]models.Article, 0, end-start)
+	for _, a := range matches[start:end] {
+		articles = append(articles, s.view(a, viewerID))
+	}
+
+	return articles, total, nil
+}
+
+func (s articleStore) GetFeed(ctx context.Context, userID string, limit, offset int) ([]models.Article, int, error) {
+	filter := models.ArticleFilter{
+		FollowedBy: userID,
+		Limit:      limit,
+		Offset:     offset,
+	}
+
+	return s.GetAll(ctx, filter, userID)
+}
+
+func (s articleStore) Update(ctx context.Context, slug string, update *models.Article) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articleBySlug(slug)
+	if !ok {
+		return notFound("article")
+	}
+
+	// If the title changed, regenerate the slug and keep the previous one. The
+	// article's own slugs are free to take again.
+	if update.Title != "" && strings.TrimSpace(update.Title) != strings.TrimSpace(a.Title) {
+		newSlug := utils.GenerateUniqueSlug(update.Title, s.similarSlugs(utils.GenerateSlug(update.Title), a.ID))
+		if newSlug != a.Slug {
+			delete(s.slugs, newSlug)
+			s.slugs[a.Slug] = a.ID
+			a.Slug = newSlug
+		}
+	}
+	if update.Title != "" {
+		a.Title = update.Title
+	}
+	if update.Description != "" {
+		a.Description = update.Description
+	}
+	if update.Body != "" {
+		a.Body = update.Body
+	}
+	if update.TagList != nil {
+		update.TagList = normalizeTags(update.TagList)
+		a.TagList = append([]string{}, update.TagList...)
+	}
+
+	a.UpdatedAt = time.Now()
+	update.UpdatedAt = a.UpdatedAt
+	update.Slug = a.Slug
+	return nil
+}
+
+func (s articleStore) Delete(ctx context.Context, slug string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	a, ok := s.articleBySlug(slug)
+	if !ok {
+		return notFound("article")
+	}
+
+	// Remove everything that references the article, like ON DELETE CASCADE
+	delete(s.articles, a.ID)
+	for previous, articleID := range s.slugs {
+		if articleID == a.ID {
+			delete(s.slugs, previous)
+		}
+	}
+	for id, c := range s.comments {
+		if c.articleID == a.ID {
+			delete(s.comments, id)
+		}
+	}
+	for f := range s.favorites {
+		if f.articleID == a.ID {
+			delete(s.favorites, f)
+		}
+	}
+
+	return nil
+}
+
+func (s articleStore) Favorite(ctx context.Context, slug, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	articleID, ok := s.resolveArticleID(slug)
+	if !ok {
+		return notFound("article")
+	}
+
+	s.favorites[favorite{userID, articleID}] = true
+	return nil
+}
+
+func (s articleStore) Unfavorite(ctx context.Context, slug, userID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	articleID, ok := s.resolveArticleID(slug)
+	if !ok {
+		return notFound("article")
+	}
+
+	delete(s.favorites, favorite{userID, articleID})
+	return nil
+}
+
+func (s articleStore) ResolveSlug(ctx context.Context, slug string) (string, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	articleID, ok := s.resolveArticleID(slug)
+	if !ok {
+		return "", notFound("article")
+	}
+
+	return s.articles[articleID].Slug, nil
+}
+
+// view renders an article for a viewer. It must be called with the lock held.
+func (s *Store) view(a *article, viewerID string) models.Article {
+	view := a.Article
+	view.TagList = append([]string{}, a.TagList...)
+
+	for f := range s.favorites {
+		if f.articleID == a.ID {
+			view.FavoritesCount++
+		}
+	}
+	view.Favorited = s.favorites[favorite{viewerID, a.ID}]
+	view.Author = s.author(a.authorID, viewerID)
+
+	return view
+}
+
+// author renders a user as an author for a viewer. It must be called with the lock held.
+func (s *Store) author(userID, viewerID string) models.Author {
+	user := s.users[userID]
+	if user == nil {
+		return models.Author{}
+	}
+
+	return models.Author{
+		Username:  user.Username,
+		Bio:       user.Bio,
+		Image:     user.Image,
+		Following: s.follows[follow{viewerID, userID}],
+	}
+}
+
+// articleBySlug finds an article by its current slug. It must be called with the lock held.
+func (s *Store) articleBySlug(slug string) (*article, bool) {
+	for _, a := range s.articles {
+		if a.Slug == slug {
+			return a, true
+		}
+	}
+	return nil, false
+}
+
+// resolveArticleID finds an article ID by its current or previous slug.
+// It must be called with the lock held.
+func (s *Store) resolveArticleID(slug string) (string, bool) {
+	if a, ok := s.articleBySlug(slug); ok {
+		return a.ID, true
+	}
+	articleID, ok := s.slugs[slug]
+	return articleID, ok
+}
+
+// similarSlugs returns current and previous slugs starting with the base slug,
+// except those of the article with excludeID. It must be called with the lock held.
+func (s *Store) similarSlugs(baseSlug, excludeID string) []string {
+	var slugs []string
+	for _, a := range s.articles {
+		if a.ID != excludeID && strings.HasPrefix(a.Slug, baseSlug) {
+			slugs = append(slugs, a.Slug)
+		}
+	}
+	for previous, articleID := range s.slugs {
+		if articleID != excludeID && strings.HasPrefix(previous, baseSlug) {
+			slugs = append(slugs, previous)
+		}
+	}
+	return slugs
+}
+
+// userIDByUsername must be called with the lock held
+func (s *Store) userIDByUsername(username string) (string, bool) {
+	for _, user := range s.users {
+		if user.Username == username {
+			return user.ID, true
+		}
+	}
+	return "", false
+}
+
+// normalizeTags trims tag names and drops blank and duplicate ones
+func normalizeTags(tags []string) []string {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" || seen[tag] {
+			continue
+		}
+		seen[tag] = true
+		normalized = append(normalized, tag)
+	}
+	return normalized
+}
+
+func containsTag(tags []string, tag string) bool {
+	for _, t := range tags {
+		if t == tag {
+			return true
+		}
+	}
+	return false
+}
+
+// Comments
+
+type commentStore struct{ *Store }
+
+func (s commentStore) Create(ctx context.Context, c *models.Comment, articleSlug, authorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	articleID, ok := s.resolveArticleID(articleSlug)
+	if !ok {
+		return fmt.Errorf("failed to get article ID: %w", notFound("article"))
+	}
+
+	c.ID = newID()
+	c.CreatedAt = time.Now()
+	c.UpdatedAt = c.CreatedAt
+
+	s.comments[c.ID] = &comment{Comment: *c, articleID: articleID, authorID: authorID, seq: s.nextSeq()}
+	return nil
+}
+
+func (s commentStore) GetByArticleSlug(ctx context.Context, articleSlug, viewerID string) ([]models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Initialize with empty slice to ensure JSON serializes as [] instead of null
+	comments := make([]models.Comment, 0)
+
+	articleID, ok := s.resolveArticleID(articleSlug)
+	if !ok {
+		return comments, nil
+	}
+
+	matches := make([]*comment, 0)
+	for _, c := range s.comments {
+		if c.articleID == articleID {
+			matches = append(matches, c)
+		}
+	}
+
+	// Oldest first
+	sort.Slice(matches, func(i, j int) bool {
+		if !matches[i].CreatedAt.Equal(matches[j].CreatedAt) {
+			return matches[i].CreatedAt.Before(matches[j].CreatedAt)
+		}
+		return matches[i].seq < matches[j].seq
+	})
+
+	for _, c := range matches {
+		view := c.Comment
+		view.Author = s.author(c.authorID, viewerID)
+		comments = append(comments, view)
+	}
+
+	return comments, nil
+}
+
+func (s commentStore) GetByID(ctx context.Context, commentID string) (*models.Comment, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	c, ok := s.comments[commentID]
+	if !ok {
+		return nil, notFound("comment")
+	}
+
+	view := c.Comment
+	view.Author = s.author(c.authorID, "")
+	return &view, nil
+}
+
+func (s commentStore) Delete(ctx context.Context, commentID, authorID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	c, ok := s.comments[commentID]
+	if !ok {
+		return notFound("comment")
+	}
+	if c.authorID != authorID {
+		return &db.Error{Kind: db.ErrForbidden, Resource: "comment"}
+	}
+
+	delete(s.comments, commentID)
+	return nil
+}
+
+// Follows
+
+type followStore struct{ *Store }
+
+func (s followStore) Follow(ctx context.Context, followerID, followingID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.follows[follow{followerID, followingID}] = true
+	return nil
+}
+
+func (s followStore) Unfollow(ctx context.Context, followerID, followingID string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	delete(s.follows, follow{followerID, followingID})
+	return nil
+}
+
+func (s followStore) IsFollowing(ctx context.Context, followerID, followingID string) (bool, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	// Anonymous viewers never follow anyone
+	if followerID == "" {
+		return false, nil
+	}
+
+	return s.follows[follow{followerID, followingID}], nil
+}
+
+// Tags
+
+type tagStore struct{ *Store }
+
+func (s tagStore) GetAll(ctx context.Context, filter models.TagFilter) ([]models.TagCount, error) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	prefix := strings.ToLower(filter.Prefix)
+	counts := make(map[string]int)
+	for _, a := range s.articles {
+		for _, tag := range a.TagList {
+			// Prefixes match case-insensitively like SQLite's LIKE
+			if strings.HasPrefix(strings.ToLower(tag), prefix) {
+				counts[tag]++
+			}
+		}
+	}
+
+	// Initialize with empty slice to ensure JSON serializes as [] instead of null
+	tags := make([]models.TagCount, 0, len(counts))
+	for tag, count := range counts {
+		tags = append(tags, models.TagCount{Tag: tag, Count: count})
+	}
+
+	// Most popular first
+	sort.Slice(tags, func(i, j int) bool {
+		if tags[i].Count != tags[j].Count {
+			return tags[i].Count > tags[j].Count
+		}
+		return tags[i].Tag < tags[j].Tag
+	})
+
+	if filter.Limit > 0 && len(tags) > filter.Limit {
+		tags = tags[:filter.Limit]
+	}
+
+	return tags, nil
+}
+
+// DeleteOrphans is a no-op because tags only exist while an article uses them
+func (s tagStore) DeleteOrphans(ctx context.Context) (int64, error) {
+	return 0, nil
+}
@@ -0,0 +1,252 @@
+package memory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func createTestUser(t *testing.T, s *Store, username string) *models.User {
+	user := &models.User{
+		Email:        username + "@example.com",
+		Username:     username,
+		PasswordHash: "hashedpassword",
+	}
+
+	if err := s.Users().Create(context.Background(), user); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	return user
+}
+
+func createTestArticle(t *testing.T, s *Store, author *models.User, title string, tags ...string) *models.Article {
+	article := &models.Article{
+		Title:       title,
+		Description: "Description",
+		Body:        "Body",
+		TagList:     tags,
+		Author:      models.Author{Username: author.Username},
+	}
+
+	if err := s.Articles().Create(context.Background(), article); err != nil {
+		t.Fatalf("Failed to create article: %v", err)
+	}
+
+	return article
+}
+
+func TestUserStore_Conflicts(t *testing.T) {
+	s := New()
+	createTestUser(t, s, "alice")
+
+	err := s.Users().Create(context.Background(), &models.User{Email: "alice@example.com", Username: "other"})
+	if !errors.Is(err, db.ErrConflict) {
+		t.Errorf("Expected conflict for duplicate email, got %v", err)
+	}
+
+	bob := createTestUser(t, s, "bob")
+	bob.Username = "alice"
+	if err := s.Users().Update(context.Background(), bob); !errors.Is(err, db.ErrConflict) {
+		t.Errorf("Expected conflict for duplicate username, got %v", err)
+	}
+
+	if _, err := s.Users().GetByID(context.Background(), "missing"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestArticleStore_SlugHistory(t *testing.T) {
+	s := New()
+	author := createTestUser(t, s, "alice")
+	article := createTestArticle(t, s, author, "First Title")
+
+	if err := s.Articles().Update(context.Background(), article.Slug, &models.Article{Title: "Second Title"}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+
+	current, err := s.Articles().ResolveSlug(context.Background(), "first-title")
+	if err != nil || current != "second-title" {
+		t.Errorf("Expected first-title to resolve to second-title, got %q, %v", current, err)
+	}
+
+	// The previous slug stays reserved for the renamed article
+	other := createTestArticle(t, s, author, "First Title")
+	if other.Slug != "first-title-1" {
+		t.Errorf("Expected first-title-1, got %q", other.Slug)
+	}
+
+	if err := s.Articles().Delete(context.Background(), "second-title"); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+	if _, err := s.Articles().ResolveSlug(context.Background(), "first-title"); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("Expected deleted article's previous slug to be gone, got %v", err)
+	}
+}
+
+func TestArticleStore_Update_SameTitleKeepsSlug(t *testing.T) {
+	s := New()
+	author := createTestUser(t, s, "alice")
+	createTestArticle(t, s, author, "Foo")
+	article := createTestArticle(t, s, author, "Foo")
+	if article.Slug != "foo-1" {
+		t.Fatalf("Expected the second article to get foo-1, got %q", article.Slug)
+	}
+
+	// Edit forms send the unchanged title with every save
+	for _, title := range []string{"Foo", " Foo ", "Foo"} {
+		update := &models.Article{Title: title, Body: "Edited"}
+		if err := s.Articles().Update(context.Background(), "foo-1", update); err != nil {
+			t.Fatalf("Failed to update article: %v", err)
+		}
+		if update.Slug != "foo-1" {
+			t.Fatalf("Expected the slug to stay foo-1, got %q", update.Slug)
+		}
+	}
+
+	if len(s.slugs) != 0 {
+		t.Errorf("Expected no slug history, got %v", s.slugs)
+	}
+}
+
+func TestArticleStore_Update_ReservedTitleKeepsSlug(t *testing.T) {
+	s := New()
+	author := createTestUser(t, s, "alice")
+	article := createTestArticle(t, s, author, "Feed")
+	if article.Slug != "feed-1" {
+		t.Fatalf("Expected feed-1 since feed is reserved, got %q", article.Slug)
+	}
+
+	// Neither the same title nor another one slugged to feed moves the slug
+	for _, title := range []string{"Feed", "FEED!", "Feed"} {
+		update := &models.Article{Title: title}
+		if err := s.Articles().Update(context.Background(), "feed-1", update); err != nil {
+			t.Fatalf("Failed to update article: %v", err)
+		}
+		if update.Slug != "feed-1" {
+			t.Fatalf("Expected the slug to stay feed-1 for title %q, got %q", title, update.Slug)
+		}
+	}
+
+	if len(s.slugs) != 0 {
+		t.Errorf("Expected no slug history, got %v", s.slugs)
+	}
+}
+
+func TestArticleStore_Update_RenameBackReclaimsSlug(t *testing.T) {
+	s := New()
+	author := createTestUser(t, s, "alice")
+	createTestArticle(t, s, author, "Foo")
+
+	if err := s.Articles().Update(context.Background(), "foo", &models.Article{Title: "Bar"}); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+
+	// The article's own previous slug does not count as taken
+	update := &models.Article{Title: "Foo"}
+	if err := s.Articles().Update(context.Background(), "bar", update); err != nil {
+		t.Fatalf("Failed to update article: %v", err)
+	}
+	if update.Slug != "foo" {
+		t.Errorf("Expected the article to take back foo, got %q", update.Slug)
+	}
+	if len(s.slugs) != 1 || s.slugs["bar"] == "" {
+		t.Errorf("Expected only bar in the slug history, got %v", s.slugs)
+	}
+}
+
+func TestArticleStore_GetAll(t *testing.T) {
+	s := New()
+	alice := createTestUser(t, s, "alice")
+	bob := createTestUser(t, s, "bob")
+
+	createTestArticle(t, s, alice, "One", "go")
+	createTestArticle(t, s, bob, "Two", "go", "web")
+	three := createTestArticle(t, s, alice, "Three")
+
+	if err := s.Articles().Favorite(context.Background(), three.Slug, bob.ID); err != nil {
+		t.Fatalf("Failed to favorite article: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		filter models.ArticleFilter
+		want   []string
+	}{
+		{"newest first", models.ArticleFilter{Limit: 20}, []string{"three", "two", "one"}},
+		{"by tag", models.ArticleFilter{Tag: "go", Limit: 20}, []string{"two", "one"}},
+		{"by author", models.ArticleFilter{Author: "alice", Limit: 20}, []string{"three", "one"}},
+		{"favorited by", models.ArticleFilter{Favorited: "bob", Limit: 20}, []string{"three"}},
+		{"paginated", models.ArticleFilter{Limit: 1, Offset: 1}, []string{"two"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, _, err := s.Articles().GetAll(context.Background(), tt.filter, bob.ID)
+			if err != nil {
+				t.Fatalf("Failed to get articles: %v", err)
+			}
+
+			var slugs []string
+			for _, a := range articles {
+				slugs = append(slugs, a.Slug)
+			}
+			if len(slugs) != len(tt.want) {
+				t.Fatalf("Expected %v, got %v", tt.want, slugs)
+			}
+			for i := range slugs {
+				if slugs[i] != tt.want[i] {
+					t.Fatalf("Expected %v, got %v", tt.want, slugs)
+				}
+			}
+		})
+	}
+
+	article, err := s.Articles().GetBySlug(context.Background(), three.Slug, bob.ID)
+	if err != nil {
+		t.Fatalf("Failed to get article: %v", err)
+	}
+	if !article.Favorited || article.FavoritesCount != 1 {
+		t.Errorf("Expected article favorited once by viewer, got %v/%d", article.Favorited, article.FavoritesCount)
+	}
+}
+
+func TestCommentStore_Delete(t *testing.T) {
+	s := New()
+	alice := createTestUser(t, s, "alice")
+	bob := createTestUser(t, s, "bob")
+	article := createTestArticle(t, s, alice, "Title")
+
+	comment := &models.Comment{Body: "Nice"}
+	if err := s.Comments().Create(context.Background(), comment, article.Slug, alice.ID); err != nil {
+		t.Fatalf("Failed to create comment: %v", err)
+	}
+
+	if err := s.Comments().Delete(context.Background(), comment.ID, bob.ID); !errors.Is(err, db.ErrForbidden) {
+		t.Errorf("Expected forbidden, got %v", err)
+	}
+	if err := s.Comments().Delete(context.Background(), comment.ID, alice.ID); err != nil {
+		t.Errorf("Expected delete to succeed, got %v", err)
+	}
+	if err := s.Comments().Delete(context.Background(), comment.ID, alice.ID); !errors.Is(err, db.ErrNotFound) {
+		t.Errorf("Expected not found, got %v", err)
+	}
+}
+
+func TestTagStore_GetAll(t *testing.T) {
+	s := New()
+	alice := createTestUser(t, s, "alice")
+	createTestArticle(t, s, alice, "One", "go", "web")
+	createTestArticle(t, s, alice, "Two", "go")
+
+	tags, err := s.Tags().GetAll(context.Background(), models.TagFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+	if len(tags) != 2 || tags[0] != (models.TagCount{Tag: "go", Count: 2}) {
+		t.Errorf("Expected go to be the most used tag, got %v", tags)
+	}
+}
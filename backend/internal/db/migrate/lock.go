@@ -0,0 +1,106 @@
+package migrate
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"io"
+	"time"
+)
+
+// advisoryLockKey identifies the migration lock among PostgreSQL advisory locks
+const advisoryLockKey = 7214053906
+
+const (
+	// lockPollInterval is how often a waiting SQLite migrator retries the lock
+	lockPollInterval = 500 * time.Millisecond
+	// staleLockAge is how old a SQLite lock row must be before it is considered
+	// left behind by a crashed process and taken over
+	staleLockAge = 15 * time.Minute
+)
+
+// lockPostgres takes a session-level advisory lock on a dedicated connection.
+// The lock is released by the returned function or when the connection closes.
+func lockPostgres(ctx context.Context, database *sql.DB) (func() error, error) {
+	conn, err := database.Conn(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := conn.ExecContext(ctx, `SELECT pg_advisory_lock($1)`, advisoryLockKey); err != nil {
+		conn.Close()
+		return nil, err
+	}
+
+	return func() error {
+		_, err := conn.ExecContext(context.Background(), `SELECT pg_advisory_unlock($1)`, advisoryLockKey)
+		if closeErr := conn.Close(); err == nil {
+			err = closeErr
+		}
+		return err
+	}, nil
+}
+
+// createLockTable creates the single-row table that serves as the SQLite lock
+func createLockTable(database *sql.DB) error {
+	_, err := database.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations_lock (
+		id INTEGER PRIMARY KEY CHECK (id = 1),
+		locked_at TIMESTAMP NOT NULL
+	)`)
+	return err
+}
+
+// lockTable holds the lock while its row exists in schema_migrations_lock.
+// SQLite has no advisory locks, so waiting migrators poll until the row is gone.
+func lockTable(ctx context.Context, database *sql.DB, out io.Writer) (func() error, error) {
+	waiting := false
+	for {
+		acquired, err := tryLockTable(ctx, database)
+		if err != nil {
+			return nil, err
+		}
+		if acquired {
+			break
+		}
+
+		if !waiting {
+			fmt.Fprintln(out, "Waiting for another process to finish migrating")
+			waiting = true
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-time.After(lockPollInterval):
+		}
+	}
+
+	return func() error {
+		_, err := database.Exec(`DELETE FROM schema_migrations_lock WHERE id = 1`)
+		return err
+	}, nil
+}
+
+// tryLockTable inserts the lock row, first removing it when it is stale
+func tryLockTable(ctx context.Context, database *sql.DB) (bool, error) {
+	now := time.Now().UTC()
+
+	if _, err := database.ExecContext(ctx,
+		`DELETE FROM schema_migrations_lock WHERE id = 1 AND locked_at < ?`, now.Add(-staleLockAge)); err != nil {
+		return false, err
+	}
+
+	result, err := database.ExecContext(ctx,
+		`INSERT OR IGNORE INTO schema_migrations_lock (id, locked_at) VALUES (1, ?)`, now)
+	if err != nil {
+		return false, err
+	}
+
+	inserted, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return inserted == 1, nil
+}
@@ -0,0 +1,104 @@
+package migrate
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestMigrator_WithLockSerializesMigrators(t *testing.T) {
+	first, _ := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+	// Both migrators find a table created before checksums were recorded and
+	// only the first one to take the lock may add the column
+	createLegacyTable(t, first)
+	second := &Migrator{db: first.db, dialect: first.dialect, migrations: first.migrations, Out: first.Out}
+
+	var mu sync.Mutex
+	running, overlapped := 0, false
+	applied := 0
+
+	var wg sync.WaitGroup
+	for _, m := range []*Migrator{first, second} {
+		wg.Add(1)
+		go func(m *Migrator) {
+			defer wg.Done()
+			err := m.WithLock(context.Background(), func() error {
+				mu.Lock()
+				running++
+				overlapped = overlapped || running > 1
+				mu.Unlock()
+
+				n, err := m.Up(0)
+
+				mu.Lock()
+				running--
+				applied += n
+				mu.Unlock()
+				return err
+			})
+			if err != nil {
+				t.Errorf("Failed to migrate under the lock: %v", err)
+			}
+		}(m)
+	}
+	wg.Wait()
+
+	if overlapped {
+		t.Error("Expected migrators to hold the lock one at a time")
+	}
+	if applied != 3 {
+		t.Errorf("Expected each migration to be applied once, got %d applications", applied)
+	}
+}
+
+func TestMigrator_WithLockWaitsForContext(t *testing.T) {
+	m, _ := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+
+	// Another process holds the lock
+	if err := createLockTable(m.db); err != nil {
+		t.Fatalf("Failed to create the lock table: %v", err)
+	}
+	if _, err := m.db.Exec(`INSERT INTO schema_migrations_lock (id, locked_at) VALUES (1, ?)`, time.Now().UTC()); err != nil {
+		t.Fatalf("Failed to take the lock: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 100*time.Millisecond)
+	defer cancel()
+
+	called := false
+	err := m.WithLock(ctx, func() error {
+		called = true
+		return nil
+	})
+	if !errors.Is(err, context.DeadlineExceeded) || called {
+		t.Errorf("Expected to give up waiting for the lock, got %v", err)
+	}
+}
+
+func TestMigrator_WithLockTakesOverStaleLock(t *testing.T) {
+	m, _ := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+
+	// A lock left behind by a process that crashed while migrating
+	if err := createLockTable(m.db); err != nil {
+		t.Fatalf("Failed to create the lock table: %v", err)
+	}
+	if _, err := m.db.Exec(`INSERT INTO schema_migrations_lock (id, locked_at) VALUES (1, ?)`, time.Now().UTC().Add(-time.Hour)); err != nil {
+		t.Fatalf("Failed to take the lock: %v", err)
+	}
+
+	called := false
+	err := m.WithLock(context.Background(), func() error {
+		called = true
+		return nil
+	})
+	if err != nil || !called {
+		t.Fatalf("Expected the stale lock to be taken over, got %v", err)
+	}
+
+	var count int
+	if err := m.db.QueryRow(`SELECT COUNT(*) FROM schema_migrations_lock`).Scan(&count); err != nil || count != 0 {
+		t.Errorf("Expected the lock to be released, got %d rows, %v", count, err)
+	}
+}
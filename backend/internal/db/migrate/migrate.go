@@ -0,0 +1,504 @@
+// Package migrate applies and reverts the schema migrations. It is shared by
+// cmd/migrate and by cmd/server when AUTO_MIGRATE is enabled.
+package migrate
+
+import (
+	"context"
+	"crypto/sha256"
+	"database/sql"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"regexp"
+	"sort"
+	"strings"
+	"text/tabwriter"
+	"time"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/migrations"
+)
+
+// migrationFilePattern matches paired migration files like "001_initial_schema.up.sql"
+var migrationFilePattern = regexp.MustCompile(`^(\d+)_(\w+)\.(up|down)\.sql$`)
+
+// Migration is a versioned schema change read from a .up.sql and an optional .down.sql file
+type Migration struct {
+	Version string
+	Name    string
+	Up      string
+	Down    string
+	// Checksum identifies the content of the up file, see checksum
+	Checksum string
+}
+
+// String returns the migration's file name prefix, e.g. "001_initial_schema"
+func (m Migration) String() string {
+	return m.Version + "_" + m.Name
+}
+
+// appliedMigration is a row of the schema_migrations table
+type appliedMigration struct {
+	Version   string
+	AppliedAt time.Time
+	// Checksum is empty for migrations applied before checksums were recorded
+	Checksum string
+}
+
+// checksum returns the SHA-256 of a migration's up script
+func checksum(script string) string {
+	sum := sha256.Sum256([]byte(script))
+	return hex.EncodeToString(sum[:])
+}
+
+// Embedded returns the migrations built into the binary for the given dialect
+func Embedded(dialect db.Dialect) fs.FS {
+	if dialect == db.Postgres {
+		sub, err := fs.Sub(migrations.FS, "postgres")
+		if err != nil {
+			// The directory is embedded, fs.Sub only fails on an invalid name
+			panic(err)
+		}
+		return sub
+	}
+	return migrations.FS
+}
+
+// Load reads the migrations at the root of fsys ordered by version.
+// Subdirectories hold the migrations of other dialects and are skipped.
+func Load(fsys fs.FS) ([]Migration, error) {
+	entries, err := fs.ReadDir(fsys, ".")
+	if err != nil {
+		return nil, err
+	}
+
+	byVersion := make(map[string]*Migration)
+	for _, entry := range entries {
+		if entry.IsDir() || !strings.HasSuffix(entry.Name(), ".sql") {
+			continue
+		}
+
+		match := migrationFilePattern.FindStringSubmatch(entry.Name())
+		if match == nil {
+			return nil, fmt.Errorf("%s is not named like 001_name.up.sql or 001_name.down.sql", entry.Name())
+		}
+		version, name, direction := match[1], match[2], match[3]
+
+		content, err := fs.ReadFile(fsys, entry.Name())
+		if err != nil {
+			return nil, err
+		}
+
+		m, ok := byVersion[version]
+		if !ok {
+			m = &Migration{Version: version, Name: name}
+			byVersion[version] = m
+		} else if m.Name != name {
+			return nil, fmt.Errorf("migrations %s and %s share version %s", m, version+"_"+name, version)
+		}
+
+		if direction == "up" {
+			m.Up = string(content)
+			m.Checksum = checksum(m.Up)
+		} else {
+			m.Down = string(content)
+		}
+	}
+
+	result := make([]Migration, 0, len(byVersion))
+	for _, m := range byVersion {
+		if m.Checksum == "" {
+			return nil, fmt.Errorf("migration %s has a down file but no up file", m)
+		}
+		result = append(result, *m)
+	}
+
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Version < result[j].Version
+	})
+
+	return result, nil
+}
+
+// Migrator applies and reverts migrations, recording them in schema_migrations
+type Migrator struct {
+	db         *sql.DB
+	dialect    db.Dialect
+	migrations []Migration
+
+	// DryRun prints the statements that would run instead of running them
+	DryRun bool
+	// Out receives progress messages, dry-run statements and the status table
+	Out io.Writer
+}
+
+// New creates a migrator for the migrations in fsys. It does not touch the
+// database: schema_migrations is created by the first command that changes the
+// schema, under the migration lock.
+func New(database *sql.DB, dialect db.Dialect, fsys fs.FS) (*Migrator, error) {
+	loaded, err := Load(fsys)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read migration files: %w", err)
+	}
+
+	return &Migrator{
+		db:         database,
+		dialect:    dialect,
+		migrations: loaded,
+		Out:        io.Discard,
+	}, nil
+}
+
+// ensureTables creates schema_migrations, adding the checksum column to tables
+// created before checksums were recorded. It must run under the migration lock,
+// otherwise servers starting at once race to create or alter the table.
+// A dry run changes nothing, so it leaves the table as it is.
+func (m *Migrator) ensureTables() error {
+	if m.DryRun {
+		return nil
+	}
+
+	_, err := m.db.Exec(`
+	CREATE TABLE IF NOT EXISTS schema_migrations (
+		version TEXT PRIMARY KEY,
+		applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+		checksum TEXT NOT NULL DEFAULT ''
+	)`)
+	if err != nil {
+		return fmt.Errorf("failed to create migrations table: %w", err)
+	}
+
+	_, hasChecksum, err := m.tableState()
+	if err != nil || hasChecksum {
+		return err
+	}
+
+	if _, err := m.db.Exec(`ALTER TABLE schema_migrations ADD COLUMN checksum TEXT NOT NULL DEFAULT ''`); err != nil {
+		return fmt.Errorf("failed to add checksum column to migrations table: %w", err)
+	}
+	return nil
+}
+
+// tableState reports whether schema_migrations exists and has the checksum column
+func (m *Migrator) tableState() (exists, hasChecksum bool, err error) {
+	query := `SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = 'schema_migrations'`
+	if m.dialect == db.Postgres {
+		query = `SELECT COUNT(*) FROM information_schema.tables WHERE table_schema = current_schema() AND table_name = 'schema_migrations'`
+	}
+
+	var count int
+	if err := m.db.QueryRow(query).Scan(&count); err != nil {
+		return false, false, err
+	}
+	if count == 0 {
+		return false, false, nil
+	}
+
+	rows, err := m.db.Query(`SELECT * FROM schema_migrations WHERE 1 = 0`)
+	if err != nil {
+		return true, false, err
+	}
+	defer rows.Close()
+
+	columns, err := rows.Columns()
+	if err != nil {
+		return true, false, err
+	}
+	for _, column := range columns {
+		if column == "checksum" {
+			return true, true, nil
+		}
+	}
+	return true, false, nil
+}
+
+// WithLock runs fn while holding the migration lock, so that of several servers
+// starting at once only one migrates and the others wait for it to finish.
+// It gives up when ctx is done before the lock is acquired.
+func (m *Migrator) WithLock(ctx context.Context, fn func() error) error {
+	var unlock func() error
+	var err error
+	if m.dialect == db.Postgres {
+		unlock, err = lockPostgres(ctx, m.db)
+	} else if err = createLockTable(m.db); err == nil {
+		unlock, err = lockTable(ctx, m.db, m.Out)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to acquire migration lock: %w", err)
+	}
+
+	fnErr := fn()
+	if err := unlock(); err != nil && fnErr == nil {
+		return fmt.Errorf("failed to release migration lock: %w", err)
+	}
+
+	return fnErr
+}
+
+// applied returns the applied migrations ordered by version. A missing
+// schema_migrations table means none are, which status and dry runs rely on
+// since they do not create it.
+func (m *Migrator) applied() ([]appliedMigration, error) {
+	exists, hasChecksum, err := m.tableState()
+	if err != nil || !exists {
+		return nil, err
+	}
+
+	query := `SELECT version, applied_at, checksum FROM schema_migrations ORDER BY version`
+	if !hasChecksum {
+		query = `SELECT version, applied_at, '' FROM schema_migrations ORDER BY version`
+	}
+
+	rows, err := m.db.Query(query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var applied []appliedMigration
+	for rows.Next() {
+		var a appliedMigration
+		if err := rows.Scan(&a.Version, &a.AppliedAt, &a.Checksum); err != nil {
+			return nil, err
+		}
+		applied = append(applied, a)
+	}
+
+	return applied, rows.Err()
+}
+
+// find returns the migration with the given version
+func (m *Migrator) find(version string) (Migration, bool) {
+	for _, mig := range m.migrations {
+		if mig.Version == version {
+			return mig, true
+		}
+	}
+	return Migration{}, false
+}
+
+// verify fails when an applied migration's up file changed since it was applied.
+// Checksums missing from older rows are recorded instead.
+func (m *Migrator) verify(applied []appliedMigration) error {
+	for _, a := range applied {
+		mig, ok := m.find(a.Version)
+		if !ok {
+			continue
+		}
+
+		if a.Checksum == "" {
+			if m.DryRun {
+				continue
+			}
+			if _, err := m.db.Exec(m.dialect.Rebind(`UPDATE schema_migrations SET checksum = ? WHERE version = ?`), mig.Checksum, a.Version); err != nil {
+				return fmt.Errorf("failed to record checksum of %s: %w", mig, err)
+			}
+			continue
+		}
+
+		if a.Checksum != mig.Checksum {
+			return fmt.Errorf("migration %s was modified after it was applied", mig)
+		}
+	}
+
+	return nil
+}
+
+// Up applies up to limit pending migrations in version order, or all of them when
+// limit is 0. It returns the number of migrations applied.
+func (m *Migrator) Up(limit int) (int, error) {
+	if err := m.ensureTables(); err != nil {
+		return 0, err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	if err := m.verify(applied); err != nil {
+		return 0, err
+	}
+
+	isApplied := make(map[string]bool, len(applied))
+	for _, a := range applied {
+		isApplied[a.Version] = true
+	}
+
+	count := 0
+	for _, mig := range m.migrations {
+		if isApplied[mig.Version] {
+			continue
+		}
+		if limit > 0 && count == limit {
+			break
+		}
+
+		if err := m.apply(mig); err != nil {
+			return count, fmt.Errorf("failed to apply migration %s: %w", mig, err)
+		}
+		count++
+	}
+
+	return count, nil
+}
+
+// Down reverts the n most recently applied migrations, newest first
+func (m *Migrator) Down(n int) error {
+	if err := m.ensureTables(); err != nil {
+		return err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	if n > len(applied) {
+		return fmt.Errorf("cannot revert %d migrations, only %d applied", n, len(applied))
+	}
+
+	for i := len(applied) - 1; i >= len(applied)-n; i-- {
+		mig, ok := m.find(applied[i].Version)
+		if !ok {
+			return fmt.Errorf("applied migration %s has no migration files", applied[i].Version)
+		}
+
+		if err := m.revert(mig); err != nil {
+			return fmt.Errorf("failed to revert migration %s: %w", mig, err)
+		}
+	}
+
+	return nil
+}
+
+// Redo reverts and reapplies the most recently applied migration
+func (m *Migrator) Redo() error {
+	if err := m.ensureTables(); err != nil {
+		return err
+	}
+
+	applied, err := m.applied()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+	if len(applied) == 0 {
+		return fmt.Errorf("no applied migration to redo")
+	}
+
+	last := applied[len(applied)-1].Version
+	mig, ok := m.find(last)
+	if !ok {
+		return fmt.Errorf("applied migration %s has no migration files", last)
+	}
+
+	if err := m.revert(mig); err != nil {
+		return fmt.Errorf("failed to revert migration %s: %w", mig, err)
+	}
+	if err := m.apply(mig); err != nil {
+		return fmt.Errorf("failed to apply migration %s: %w", mig, err)
+	}
+
+	return nil
+}
+
+// Status prints every migration with whether it is applied, pending or was
+// modified after it was applied
+func (m *Migrator) Status() error {
+	applied, err := m.applied()
+	if err != nil {
+		return fmt.Errorf("failed to get applied migrations: %w", err)
+	}
+
+	appliedByVersion := make(map[string]appliedMigration, len(applied))
+	for _, a := range applied {
+		appliedByVersion[a.Version] = a
+	}
+
+	w := tabwriter.NewWriter(m.Out, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "VERSION\tNAME\tSTATUS\tAPPLIED AT")
+
+	for _, mig := range m.migrations {
+		a, ok := appliedByVersion[mig.Version]
+		switch {
+		case !ok:
+			fmt.Fprintf(w, "%s\t%s\tpending\t\n", mig.Version, mig.Name)
+		case a.Checksum != "" && a.Checksum != mig.Checksum:
+			fmt.Fprintf(w, "%s\t%s\tmodified\t%s\n", mig.Version, mig.Name, a.AppliedAt.Format(time.RFC3339))
+		default:
+			fmt.Fprintf(w, "%s\t%s\tapplied\t%s\n", mig.Version, mig.Name, a.AppliedAt.Format(time.RFC3339))
+		}
+	}
+
+	// Rows without files, e.g. migrations deleted after they were applied
+	for _, a := range applied {
+		if _, ok := m.find(a.Version); !ok {
+			fmt.Fprintf(w, "%s\t\tmissing\t%s\n", a.Version, a.AppliedAt.Format(time.RFC3339))
+		}
+	}
+
+	return w.Flush()
+}
+
+// apply runs a migration's up script and records it in one transaction
+func (m *Migrator) apply(mig Migration) error {
+	m.announce("Applying", mig)
+
+	return m.run(mig.Up,
+		`INSERT INTO schema_migrations (version, checksum) VALUES (?, ?)`, mig.Version, mig.Checksum)
+}
+
+// revert runs a migration's down script and forgets it in one transaction
+func (m *Migrator) revert(mig Migration) error {
+	if strings.TrimSpace(mig.Down) == "" {
+		return fmt.Errorf("migration has no %s.down.sql", mig)
+	}
+
+	m.announce("Reverting", mig)
+
+	return m.run(mig.Down,
+		`DELETE FROM schema_migrations WHERE version = ?`, mig.Version)
+}
+
+// announce prints the migration about to run, as a SQL comment in dry-run mode
+// so the output stays a valid script
+func (m *Migrator) announce(action string, mig Migration) {
+	if m.DryRun {
+		fmt.Fprintf(m.Out, "-- %s migration %s (dry run)\n", action, mig)
+		return
+	}
+	fmt.Fprintf(m.Out, "%s migration %s\n", action, mig)
+}
+
+// run executes the statements of a script followed by the bookkeeping query in a
+// transaction. In dry-run mode the statements are printed instead.
+func (m *Migrator) run(script, bookkeeping string, args ...interface{}) error {
+	statements := splitStatements(script)
+
+	if m.DryRun {
+		for _, stmt := range statements {
+			fmt.Fprintf(m.Out, "%s;\n\n", stmt)
+		}
+		return nil
+	}
+
+	tx, err := m.db.Begin()
+	if err != nil {
+		return err
+	}
+	// Rollback is a no-op once the transaction has been committed
+	defer func() { _ = tx.Rollback() }()
+
+	for _, stmt := range statements {
+		if _, err := tx.Exec(stmt); err != nil {
+			return fmt.Errorf("failed to execute statement: %s, error: %w", stmt, err)
+		}
+	}
+
+	if _, err := tx.Exec(m.dialect.Rebind(bookkeeping), args...); err != nil {
+		return err
+	}
+
+	return tx.Commit()
+}
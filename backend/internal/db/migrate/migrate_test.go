@@ -0,0 +1,280 @@
+package migrate
+
+import (
+	"bytes"
+	"database/sql"
+	"io/fs"
+	"path/filepath"
+	"strings"
+	"testing"
+	"testing/fstest"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+)
+
+func openTestDB(t *testing.T) *sql.DB {
+	database, err := sql.Open("sqlite3", filepath.Join(t.TempDir(), "test.db"))
+	if err != nil {
+		t.Fatalf("Failed to create test database: %v", err)
+	}
+	t.Cleanup(func() { database.Close() })
+	return database
+}
+
+func setupTestMigrator(t *testing.T, fsys fs.FS) (*Migrator, *bytes.Buffer) {
+	m, err := New(openTestDB(t), db.SQLite, fsys)
+	if err != nil {
+		t.Fatalf("Failed to create migrator: %v", err)
+	}
+
+	var out bytes.Buffer
+	m.Out = &out
+	return m, &out
+}
+
+func migrationFiles(files map[string]string) fstest.MapFS {
+	fsys := fstest.MapFS{}
+	for name, content := range files {
+		fsys[name] = &fstest.MapFile{Data: []byte(content)}
+	}
+	return fsys
+}
+
+func appliedVersions(t *testing.T, m *Migrator) []string {
+	applied, err := m.applied()
+	if err != nil {
+		t.Fatalf("Failed to get applied migrations: %v", err)
+	}
+
+	versions := []string{}
+	for _, a := range applied {
+		versions = append(versions, a.Version)
+	}
+	return versions
+}
+
+func tableExists(t *testing.T, m *Migrator, table string) bool {
+	var count int
+	err := m.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name = ?`, table).Scan(&count)
+	if err != nil {
+		t.Fatalf("Failed to check table %s: %v", table, err)
+	}
+	return count > 0
+}
+
+// createLegacyTable creates schema_migrations as it was before checksums were recorded
+func createLegacyTable(t *testing.T, m *Migrator) {
+	if _, err := m.db.Exec(`CREATE TABLE schema_migrations (version TEXT PRIMARY KEY, applied_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP)`); err != nil {
+		t.Fatalf("Failed to create migrations table: %v", err)
+	}
+}
+
+var testMigrationFiles = map[string]string{
+	"001_users.up.sql":   "CREATE TABLE users (id TEXT);",
+	"001_users.down.sql": "DROP TABLE users;",
+	"002_posts.up.sql":   "CREATE TABLE posts (id TEXT);\nCREATE TABLE drafts (id TEXT);",
+	"002_posts.down.sql": "DROP TABLE drafts;\nDROP TABLE posts;",
+	"003_likes.up.sql":   "CREATE TABLE likes (id TEXT);",
+	"003_likes.down.sql": "DROP TABLE likes;",
+	"README.md":          "ignored",
+}
+
+func TestMigrator_UpDownRedo(t *testing.T) {
+	m, _ := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	if got := appliedVersions(t, m); strings.Join(got, ",") != "001,002,003" {
+		t.Fatalf("Expected every migration applied, got %v", got)
+	}
+
+	if err := m.Down(2); err != nil {
+		t.Fatalf("Failed to migrate down: %v", err)
+	}
+	if got := appliedVersions(t, m); strings.Join(got, ",") != "001" {
+		t.Fatalf("Expected only 001 applied, got %v", got)
+	}
+	if tableExists(t, m, "posts") || tableExists(t, m, "likes") || !tableExists(t, m, "users") {
+		t.Error("Expected down to drop the tables of reverted migrations only")
+	}
+
+	if err := m.Redo(); err != nil {
+		t.Fatalf("Failed to redo: %v", err)
+	}
+	if got := appliedVersions(t, m); strings.Join(got, ",") != "001" || !tableExists(t, m, "users") {
+		t.Fatalf("Expected redo to reapply 001, got %v", got)
+	}
+
+	if err := m.Down(5); err == nil {
+		t.Error("Expected reverting more migrations than applied to fail")
+	}
+}
+
+func TestMigrator_DetectsModifiedMigration(t *testing.T) {
+	fsys := migrationFiles(map[string]string{
+		"001_users.up.sql": "CREATE TABLE users (id TEXT);",
+	})
+	m, out := setupTestMigrator(t, fsys)
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	// Edit the applied migration and add a new one
+	m.migrations[0].Up = "CREATE TABLE users (id TEXT, email TEXT);"
+	m.migrations[0].Checksum = checksum(m.migrations[0].Up)
+	m.migrations = append(m.migrations, Migration{Version: "002", Name: "posts", Up: "CREATE TABLE posts (id TEXT);", Checksum: checksum("CREATE TABLE posts (id TEXT);")})
+
+	if _, err := m.Up(0); err == nil || !strings.Contains(err.Error(), "001_users was modified") {
+		t.Fatalf("Expected modified migration error, got %v", err)
+	}
+	if tableExists(t, m, "posts") {
+		t.Error("Expected no migration to run once a modification is detected")
+	}
+
+	out.Reset()
+	if err := m.Status(); err != nil {
+		t.Fatalf("Failed to print status: %v", err)
+	}
+	if !strings.Contains(out.String(), "modified") || !strings.Contains(out.String(), "pending") {
+		t.Errorf("Expected status to report modified and pending migrations, got:\n%s", out.String())
+	}
+}
+
+func TestMigrator_RecordsMissingChecksums(t *testing.T) {
+	fsys := migrationFiles(map[string]string{
+		"001_users.up.sql": "CREATE TABLE users (id TEXT);",
+	})
+	m, _ := setupTestMigrator(t, fsys)
+
+	// A row written before checksums were recorded
+	createLegacyTable(t, m)
+	if _, err := m.db.Exec(`INSERT INTO schema_migrations (version) VALUES ('001')`); err != nil {
+		t.Fatalf("Failed to insert migration row: %v", err)
+	}
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+
+	applied, err := m.applied()
+	if err != nil || len(applied) != 1 || applied[0].Checksum != m.migrations[0].Checksum {
+		t.Errorf("Expected the checksum to be recorded, got %+v, %v", applied, err)
+	}
+}
+
+func TestMigrator_DryRun(t *testing.T) {
+	m, out := setupTestMigrator(t, migrationFiles(map[string]string{
+		"001_users.up.sql": "-- users\nCREATE TABLE users (id TEXT);",
+	}))
+	m.DryRun = true
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to dry run: %v", err)
+	}
+
+	if tableExists(t, m, "users") || tableExists(t, m, "schema_migrations") || tableExists(t, m, "schema_migrations_lock") {
+		t.Error("Expected dry run to leave the database unchanged")
+	}
+	if !strings.Contains(out.String(), "CREATE TABLE users (id TEXT);") {
+		t.Errorf("Expected dry run to print the statements, got:\n%s", out.String())
+	}
+}
+
+func TestMigrator_DryRunLegacyTable(t *testing.T) {
+	m, out := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+	createLegacyTable(t, m)
+	if _, err := m.db.Exec(`INSERT INTO schema_migrations (version) VALUES ('001')`); err != nil {
+		t.Fatalf("Failed to insert migration row: %v", err)
+	}
+	m.DryRun = true
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to dry run: %v", err)
+	}
+
+	if strings.Contains(out.String(), "001_users") || !strings.Contains(out.String(), "002_posts") {
+		t.Errorf("Expected dry run to skip applied migrations, got:\n%s", out.String())
+	}
+	if _, hasChecksum, err := m.tableState(); err != nil || hasChecksum {
+		t.Errorf("Expected dry run to leave the checksum column out, got %v, %v", hasChecksum, err)
+	}
+}
+
+func TestMigrator_StatusWithoutTable(t *testing.T) {
+	m, out := setupTestMigrator(t, migrationFiles(testMigrationFiles))
+
+	if err := m.Status(); err != nil {
+		t.Fatalf("Failed to print status: %v", err)
+	}
+	if strings.Count(out.String(), "pending") != 3 {
+		t.Errorf("Expected every migration pending, got:\n%s", out.String())
+	}
+	if tableExists(t, m, "schema_migrations") {
+		t.Error("Expected status to leave the database unchanged")
+	}
+}
+
+func TestLoad_Invalid(t *testing.T) {
+	tests := map[string]map[string]string{
+		"unpaired name":       {"001_schema.sql": "SELECT 1;"},
+		"down without up":     {"001_schema.down.sql": "SELECT 1;"},
+		"conflicting version": {"001_a.up.sql": "SELECT 1;", "001_b.up.sql": "SELECT 1;"},
+	}
+
+	for name, files := range tests {
+		t.Run(name, func(t *testing.T) {
+			if _, err := Load(migrationFiles(files)); err == nil {
+				t.Error("Expected an error")
+			}
+		})
+	}
+}
+
+func TestRepositoryMigrations_RoundTrip(t *testing.T) {
+	m, _ := setupTestMigrator(t, Embedded(db.SQLite))
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up: %v", err)
+	}
+	if err := m.Down(len(m.migrations)); err != nil {
+		t.Fatalf("Failed to migrate down: %v", err)
+	}
+
+	var count int
+	if err := m.db.QueryRow(`SELECT COUNT(*) FROM sqlite_master WHERE type = 'table' AND name NOT IN ('schema_migrations', 'schema_migrations_lock')`).Scan(&count); err != nil {
+		t.Fatalf("Failed to count tables: %v", err)
+	}
+	if count != 0 {
+		t.Errorf("Expected down migrations to drop every table, %d left", count)
+	}
+
+	if _, err := m.Up(0); err != nil {
+		t.Fatalf("Failed to migrate up again: %v", err)
+	}
+}
+
+func TestEmbedded_Postgres(t *testing.T) {
+	sqlite, err := Load(Embedded(db.SQLite))
+	if err != nil {
+		t.Fatalf("Failed to load SQLite migrations: %v", err)
+	}
+	postgres, err := Load(Embedded(db.Postgres))
+	if err != nil {
+		t.Fatalf("Failed to load PostgreSQL migrations: %v", err)
+	}
+
+	if len(postgres) != len(sqlite) {
+		t.Fatalf("Expected a PostgreSQL migration for each SQLite one, got %d and %d", len(postgres), len(sqlite))
+	}
+	for i := range sqlite {
+		if postgres[i].String() != sqlite[i].String() {
+			t.Errorf("Expected migration %s, got %s", sqlite[i], postgres[i])
+		}
+		if postgres[i].Up == sqlite[i].Up {
+			t.Errorf("Expected migration %s to differ between dialects", postgres[i])
+		}
+	}
+}
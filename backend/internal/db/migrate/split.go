@@ -0,0 +1,135 @@
+package migrate
+
+import (
+	"strings"
+	"unicode"
+)
+
+// splitStatements splits a SQL script into the statements it contains.
+// Semicolons only end a statement outside of quoted strings and identifiers,
+// comments, PostgreSQL dollar-quoted bodies and the BEGIN ... END body of a
+// CREATE TRIGGER. Statements are trimmed, and those holding nothing but
+// comments are dropped.
+func splitStatements(script string) []string {
+	var statements []string
+
+	start := 0
+	hasCode := false
+
+	// Trigger bodies are tracked by keyword: words counts the words of the
+	// current statement and depth the open BEGIN and CASE blocks of a trigger
+	words := 0
+	createStatement, trigger := false, false
+	depth := 0
+
+	endStatement := func(end int) {
+		if hasCode {
+			statements = append(statements, strings.TrimSpace(script[start:end]))
+		}
+		start = end + 1
+		hasCode = false
+		words = 0
+		createStatement, trigger = false, false
+		depth = 0
+	}
+
+	for i := 0; i < len(script); i++ {
+		c := script[i]
+
+		switch {
+		case c == '-' && strings.HasPrefix(script[i:], "--"):
+			i = skipUntil(script, i+2, "\n") - 1
+			continue
+		case c == '/' && strings.HasPrefix(script[i:], "/*"):
+			i = skipUntil(script, i+2, "*/") - 1
+			continue
+		case unicode.IsSpace(rune(c)):
+			continue
+		}
+
+		hasCode = true
+
+		switch {
+		case c == '\'' || c == '"' || c == '`':
+			i = skipQuoted(script, i, c) - 1
+		case c == '$':
+			if tag, ok := dollarQuoteTag(script[i:]); ok {
+				i = skipUntil(script, i+len(tag), tag) - 1
+			}
+		case c == ';':
+			if depth == 0 {
+				endStatement(i)
+			}
+		case isWordByte(c):
+			j := i
+			for j < len(script) && isWordByte(script[j]) {
+				j++
+			}
+
+			word := strings.ToUpper(script[i:j])
+			words++
+			switch {
+			case words == 1:
+				createStatement = word == "CREATE"
+			case createStatement && !trigger && word == "TRIGGER":
+				trigger = true
+			case trigger && word == "BEGIN":
+				depth++
+			case trigger && depth > 0 && word == "CASE":
+				depth++
+			case trigger && depth > 0 && word == "END":
+				depth--
+			}
+
+			i = j - 1
+		}
+	}
+
+	endStatement(len(script))
+	return statements
+}
+
+// skipUntil returns the index just past the first occurrence of terminator at or
+// after from, or the end of the script when there is none
+func skipUntil(script string, from int, terminator string) int {
+	if i := strings.Index(script[from:], terminator); i >= 0 {
+		return from + i + len(terminator)
+	}
+	return len(script)
+}
+
+// skipQuoted returns the index just past the quoted string or identifier starting
+// at i. A doubled quote character inside it is an escaped quote.
+func skipQuoted(script string, i int, quote byte) int {
+	for j := i + 1; j < len(script); j++ {
+		if script[j] != quote {
+			continue
+		}
+		if j+1 < len(script) && script[j+1] == quote {
+			j++
+			continue
+		}
+		return j + 1
+	}
+	return len(script)
+}
+
+// dollarQuoteTag returns the opening tag, such as "$$" or "$body$", of a
+// PostgreSQL dollar-quoted string at the start of s. Positional parameters like
+// $1 are not dollar quotes.
+func dollarQuoteTag(s string) (string, bool) {
+	for j := 1; j < len(s); j++ {
+		switch c := s[j]; {
+		case c == '$':
+			return s[:j+1], true
+		case c == '_' || unicode.IsLetter(rune(c)) || (j > 1 && unicode.IsDigit(rune(c))):
+		default:
+			return "", false
+		}
+	}
+	return "", false
+}
+
+func isWordByte(c byte) bool {
+	return c == '_' || c >= 0x80 || unicode.IsLetter(rune(c)) || unicode.IsDigit(rune(c))
+}
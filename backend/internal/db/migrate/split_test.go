@@ -0,0 +1,95 @@
+package migrate
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSplitStatements(t *testing.T) {
+	tests := []struct {
+		name   string
+		script string
+		want   []string
+	}{
+		{
+			name:   "plain statements",
+			script: "CREATE TABLE a (id TEXT);\nCREATE TABLE b (id TEXT);\n",
+			want:   []string{"CREATE TABLE a (id TEXT)", "CREATE TABLE b (id TEXT)"},
+		},
+		{
+			name:   "missing final semicolon",
+			script: "DROP TABLE a;\nDROP TABLE b",
+			want:   []string{"DROP TABLE a", "DROP TABLE b"},
+		},
+		{
+			name:   "semicolons in strings and identifiers",
+			script: "INSERT INTO a VALUES ('x;y', 'it''s; fine');\nSELECT \"odd;name\" FROM a;",
+			want:   []string{"INSERT INTO a VALUES ('x;y', 'it''s; fine')", "SELECT \"odd;name\" FROM a"},
+		},
+		{
+			name:   "semicolons in comments",
+			script: "-- first; table\nCREATE TABLE a (id TEXT); /* done; */\n-- trailing; comment\n",
+			want:   []string{"-- first; table\nCREATE TABLE a (id TEXT)"},
+		},
+		{
+			name: "sqlite trigger",
+			script: `CREATE TRIGGER touch AFTER UPDATE ON a
+BEGIN
+    UPDATE a SET updated_at = CASE WHEN 1 THEN CURRENT_TIMESTAMP ELSE NULL END WHERE id = NEW.id;
+    INSERT INTO log VALUES ('end;');
+END;
+DROP TABLE b;`,
+			want: []string{
+				`CREATE TRIGGER touch AFTER UPDATE ON a
+BEGIN
+    UPDATE a SET updated_at = CASE WHEN 1 THEN CURRENT_TIMESTAMP ELSE NULL END WHERE id = NEW.id;
+    INSERT INTO log VALUES ('end;');
+END`,
+				"DROP TABLE b",
+			},
+		},
+		{
+			name: "postgres dollar quoted function",
+			script: `CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql;
+CREATE TRIGGER touch BEFORE UPDATE ON a FOR EACH ROW EXECUTE FUNCTION touch();`,
+			want: []string{
+				`CREATE FUNCTION touch() RETURNS trigger AS $body$
+BEGIN
+    NEW.updated_at = now();
+    RETURN NEW;
+END;
+$body$ LANGUAGE plpgsql`,
+				"CREATE TRIGGER touch BEFORE UPDATE ON a FOR EACH ROW EXECUTE FUNCTION touch()",
+			},
+		},
+		{
+			name:   "positional parameters are not dollar quotes",
+			script: "UPDATE a SET id = $1 WHERE id = $2; DROP TABLE a;",
+			want:   []string{"UPDATE a SET id = $1 WHERE id = $2", "DROP TABLE a"},
+		},
+		{
+			name:   "begin transaction outside a trigger",
+			script: "BEGIN; DROP TABLE a; COMMIT;",
+			want:   []string{"BEGIN", "DROP TABLE a", "COMMIT"},
+		},
+		{
+			name:   "only comments",
+			script: "-- nothing to do\n/* really */\n",
+			want:   nil,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := splitStatements(tt.script)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("splitStatements() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
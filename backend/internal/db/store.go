@@ -0,0 +1,63 @@
+package db
+
+import (
+	"context"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+// UserStore persists users
+type UserStore interface {
+	Create(ctx context.Context, user *models.User) error
+	GetByEmail(ctx context.Context, email string) (*models.User, error)
+	GetByID(ctx context.Context, id string) (*models.User, error)
+	GetByUsername(ctx context.Context, username string) (*models.User, error)
+	EmailExists(ctx context.Context, email string) (bool, error)
+	UsernameExists(ctx context.Context, username string) (bool, error)
+	Update(ctx context.Context, user *models.User) error
+}
+
+// ArticleStore persists articles, their tags and favorites.
+// viewerID parameters name the user the articles are rendered for and may be empty.
+type ArticleStore interface {
+	Create(ctx context.Context, article *models.Article) error
+	GetBySlug(ctx context.Context, slug, viewerID string) (*models.Article, error)
+	GetAll(ctx context.Context, filter models.ArticleFilter, viewerID string) ([]models.Article, int, error)
+	GetFeed(ctx context.Context, userID string, limit, offset int) ([]models.Article, int, error)
+	Update(ctx context.Context, slug string, article *models.Article) error
+	Delete(ctx context.Context, slug string) error
+	Favorite(ctx context.Context, slug, userID string) error
+	Unfavorite(ctx context.Context, slug, userID string) error
+	ResolveSlug(ctx context.Context, slug string) (string, error)
+}
+
+// CommentStore persists comments on articles
+type CommentStore interface {
+	Create(ctx context.Context, comment *models.Comment, articleSlug, authorID string) error
+	GetByArticleSlug(ctx context.Context, articleSlug, viewerID string) ([]models.Comment, error)
+	GetByID(ctx context.Context, commentID string) (*models.Comment, error)
+	Delete(ctx context.Context, commentID, authorID string) error
+}
+
+// FollowStore persists follow relationships between users
+type FollowStore interface {
+	Follow(ctx context.Context, followerID, followingID string) error
+	Unfollow(ctx context.Context, followerID, followingID string) error
+	IsFollowing(ctx context.Context, followerID, followingID string) (bool, error)
+}
+
+// TagStore reads the tags used by articles
+type TagStore interface {
+	GetAll(ctx context.Context, filter models.TagFilter) ([]models.TagCount, error)
+	DeleteOrphans(ctx context.Context) (int64, error)
+}
+
+// The SQL repositories implement every store for both SQLite and PostgreSQL.
+// The memory package implements them too, for tests and -storage=memory.
+var (
+	_ UserStore    = (*UserRepository)(nil)
+	_ ArticleStore = (*ArticleRepository)(nil)
+	_ CommentStore = (*CommentRepository)(nil)
+	_ FollowStore  = (*FollowRepository)(nil)
+	_ TagStore     = (*TagRepository)(nil)
+)
@@ -0,0 +1,99 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+// TagRepository handles tag data operations
+type TagRepository struct {
+	db *conn
+}
+
+// NewTagRepository creates a new tag repository
+func NewTagRepository(db *sql.DB) *TagRepository {
+	return &TagRepository{db: newConn(db)}
+}
+
+// GetAll retrieves tags used by at least one article, most popular first
+func (r *TagRepository) GetAll(ctx context.Context, filter models.TagFilter) ([]models.TagCount, error) {
+	ctx, span := startSpan(ctx, "TagRepository.GetAll")
+	defer span.End()
+
+	whereClause := ""
+	args := []interface{}{}
+
+	if filter.Prefix != "" {
+		whereClause = ` WHERE t.name ` + r.db.dialect.caseInsensitiveLike() + ` ? ESCAPE '\'`
+		args = append(args, escapeLike(filter.Prefix)+"%")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT t.name, COUNT(at.article_id) as usage_count
+		FROM tags t
+		JOIN article_tags at ON t.id = at.tag_id
+		%s
+		GROUP BY t.id, t.name
+		ORDER BY usage_count DESC, t.name ASC
+	`, whereClause)
+
+	if filter.Limit > 0 {
+		query += " LIMIT ?"
+		args = append(args, filter.Limit)
+	}
+
+	rows, err := r.db.Query(ctx, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query tags: %w", err)
+	}
+	defer rows.Close()
+
+	// Initialize with empty slice to ensure JSON serializes as [] instead of null
+	tags := make([]models.TagCount, 0)
+	for rows.Next() {
+		var tag models.TagCount
+		if err := rows.Scan(&tag.Tag, &tag.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error iterating tags: %w", err)
+	}
+
+	return tags, nil
+}
+
+// DeleteOrphans removes tags that are no longer referenced by any article
+func (r *TagRepository) DeleteOrphans(ctx context.Context) (int64, error) {
+	ctx, span := startSpan(ctx, "TagRepository.DeleteOrphans")
+	defer span.End()
+
+	return deleteOrphanTags(ctx, r.db)
+}
+
+// deleteOrphanTags removes tags that are no longer referenced by any article
+func deleteOrphanTags(ctx context.Context, db dbtx) (int64, error) {
+	result, err := db.Exec(ctx, `DELETE FROM tags WHERE id NOT IN (SELECT tag_id FROM article_tags)`)
+	if err != nil {
+		return 0, fmt.Errorf("failed to delete orphan tags: %w", err)
+	}
+
+	deleted, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return deleted, nil
+}
+
+// escapeLike escapes LIKE wildcards so user input is matched literally
+func escapeLike(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`)
+	return replacer.Replace(value)
+}
@@ -0,0 +1,94 @@
+package db
+
+import (
+	"context"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func TestTagRepository_GetAll(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	createTestArticle(t, db, author, "Go Basics", "go", "backend")
+	createTestArticle(t, db, author, "Go Advanced", "go", "golang")
+	createTestArticle(t, db, author, "Frontend", "react", "go_fast")
+
+	repo := NewTagRepository(db)
+
+	tags, err := repo.GetAll(context.Background(), models.TagFilter{})
+	if err != nil {
+		t.Fatalf("Failed to get tags: %v", err)
+	}
+
+	if len(tags) != 5 {
+		t.Fatalf("Expected 5 tags, got %v", tags)
+	}
+
+	// Most popular tag comes first, ties are ordered by name
+	if tags[0].Tag != "go" || tags[0].Count != 2 {
+		t.Errorf("Expected go with 2 articles first, got %+v", tags[0])
+	}
+	if tags[1].Tag != "backend" {
+		t.Errorf("Expected backend second, got %+v", tags[1])
+	}
+
+	// Prefix search treats wildcards literally
+	tags, err = repo.GetAll(context.Background(), models.TagFilter{Prefix: "go_"})
+	if err != nil {
+		t.Fatalf("Failed to get tags by prefix: %v", err)
+	}
+	if len(tags) != 1 || tags[0].Tag != "go_fast" {
+		t.Errorf("Expected only go_fast, got %v", tags)
+	}
+
+	tags, err = repo.GetAll(context.Background(), models.TagFilter{Prefix: "go", Limit: 2})
+	if err != nil {
+		t.Fatalf("Failed to get tags by prefix: %v", err)
+	}
+	if len(tags) != 2 || tags[0].Tag != "go" {
+		t.Errorf("Expected 2 tags starting with go, got %v", tags)
+	}
+}
+
+func TestTagRepository_OrphanCleanupOnArticleDelete(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	shared := createTestArticle(t, db, author, "Shared", "common", "unique")
+	createTestArticle(t, db, author, "Other", "common")
+
+	if err := NewArticleRepository(db).Delete(context.Background(), shared.Slug); err != nil {
+		t.Fatalf("Failed to delete article: %v", err)
+	}
+
+	var names []string
+	rows, err := db.Query(`SELECT name FROM tags ORDER BY name`)
+	if err != nil {
+		t.Fatalf("Failed to query tags: %v", err)
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("Failed to scan tag: %v", err)
+		}
+		names = append(names, name)
+	}
+
+	if len(names) != 1 || names[0] != "common" {
+		t.Errorf("Expected only the still referenced tag to remain, got %v", names)
+	}
+
+	// Nothing left to clean up
+	removed, err := NewTagRepository(db).DeleteOrphans(context.Background())
+	if err != nil {
+		t.Fatalf("Failed to delete orphan tags: %v", err)
+	}
+	if removed != 0 {
+		t.Errorf("Expected no orphan tags, removed %d", removed)
+	}
+}
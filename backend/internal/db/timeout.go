@@ -0,0 +1,71 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// DefaultQueryTimeout is the query timeout used unless SetQueryTimeout is called
+const DefaultQueryTimeout = 5 * time.Second
+
+var queryTimeout = DefaultQueryTimeout
+
+// SetQueryTimeout sets how long a single statement may run before it is canceled
+// and fails with ErrTimeout. Zero disables the limit. It is meant to be called
+// once at startup.
+func SetQueryTimeout(timeout time.Duration) {
+	queryTimeout = timeout
+}
+
+// withQueryTimeout returns a copy of ctx that expires after the query timeout
+func withQueryTimeout(ctx context.Context) (context.Context, context.CancelFunc) {
+	if queryTimeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, queryTimeout)
+}
+
+// queryError wraps the error of a statement run with ctx in ErrTimeout when ctx
+// expired. Drivers report an interrupted statement in their own words, so the
+// context is checked rather than the error.
+func queryError(ctx context.Context, err error) error {
+	if err == nil || !errors.Is(ctx.Err(), context.DeadlineExceeded) {
+		return err
+	}
+	return fmt.Errorf("%w: %w", ErrTimeout, err)
+}
+
+// timedRow is the result of QueryRow, the query timeout ends once it is scanned
+type timedRow struct {
+	*sql.Row
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Scan copies the row into dest like sql.Row.Scan and releases the timeout
+func (r *timedRow) Scan(dest ...interface{}) error {
+	defer r.cancel()
+	return queryError(r.ctx, r.Row.Scan(dest...))
+}
+
+// timedRows is the result of Query, the query timeout ends once they are closed
+type timedRows struct {
+	*sql.Rows
+	ctx    context.Context
+	cancel context.CancelFunc
+}
+
+// Err returns the error that ended the iteration, ErrTimeout when the timeout expired
+func (r *timedRows) Err() error {
+	return queryError(r.ctx, r.Rows.Err())
+}
+
+// Close closes the rows and releases the timeout
+func (r *timedRows) Close() error {
+	err := r.Rows.Close()
+	r.cancel()
+	return err
+}
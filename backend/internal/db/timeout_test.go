@@ -0,0 +1,79 @@
+package db
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+// slowQuery counts far enough to run for seconds in SQLite
+const slowQuery = `
+	WITH RECURSIVE c(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM c WHERE x < 1000000000)
+	SELECT COUNT(*) FROM c`
+
+// setQueryTimeout changes the query timeout for the duration of the test
+func setQueryTimeout(t *testing.T, timeout time.Duration) {
+	previous := queryTimeout
+	SetQueryTimeout(timeout)
+	t.Cleanup(func() { SetQueryTimeout(previous) })
+}
+
+func TestQueryTimeout(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	setQueryTimeout(t, 50*time.Millisecond)
+
+	var count int
+	start := time.Now()
+	err := newConn(db).QueryRow(context.Background(), slowQuery).Scan(&count)
+
+	if !errors.Is(err, ErrTimeout) {
+		t.Fatalf("Expected ErrTimeout, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 2*time.Second {
+		t.Errorf("Expected the query to be interrupted, it ran for %v", elapsed)
+	}
+
+	// The repositories wrap the error, handlers still recognize it
+	_, _, err = NewArticleRepository(db).GetAll(expiredContext(t), models.ArticleFilter{Limit: 20}, "")
+	if !errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected a repository to return ErrTimeout, got %v", err)
+	}
+}
+
+func TestQueryTimeout_Disabled(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	setQueryTimeout(t, 0)
+
+	var one int
+	if err := newConn(db).QueryRow(context.Background(), "SELECT 1").Scan(&one); err != nil || one != 1 {
+		t.Fatalf("Expected the query to run without a timeout, got %d, %v", one, err)
+	}
+}
+
+func TestQueryTimeout_Canceled(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	// A client that disconnects cancels the request, which is not a timeout
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	_, err := newConn(db).Exec(ctx, "DELETE FROM articles")
+	if err == nil || errors.Is(err, ErrTimeout) {
+		t.Errorf("Expected a cancellation error other than ErrTimeout, got %v", err)
+	}
+}
+
+// expiredContext returns a context whose deadline has passed
+func expiredContext(t *testing.T) context.Context {
+	ctx, cancel := context.WithDeadline(context.Background(), time.Now().Add(-time.Second))
+	t.Cleanup(cancel)
+	return ctx
+}
@@ -0,0 +1,59 @@
+package db
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracer creates the spans of repository methods and their SQL statements. It
+// is looked up through the global provider, which is a no-op until tracing is
+// set up.
+var tracer = otel.Tracer("github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db")
+
+// startSpan starts the span of a repository method, named like "ArticleRepository.GetAll"
+func startSpan(ctx context.Context, name string) (context.Context, trace.Span) {
+	return tracer.Start(ctx, name)
+}
+
+// startQuerySpan starts a client span for one SQL statement, named after its
+// first keyword such as SELECT. The statement is recorded with its placeholders,
+// never the argument values.
+func (d Dialect) startQuerySpan(ctx context.Context, query string) (context.Context, trace.Span) {
+	query = strings.TrimSpace(query)
+
+	name := "SQL"
+	if fields := strings.Fields(query); len(fields) > 0 {
+		name = strings.ToUpper(fields[0])
+	}
+
+	return tracer.Start(ctx, name,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(d.systemAttribute(), semconv.DBQueryText(query)),
+	)
+}
+
+// systemAttribute returns the db.system attribute of the dialect
+func (d Dialect) systemAttribute() attribute.KeyValue {
+	if d == Postgres {
+		return semconv.DBSystemPostgreSQL
+	}
+	return semconv.DBSystemSqlite
+}
+
+// endQuerySpan marks the span as failed for errors other than sql.ErrNoRows, which
+// repositories turn into not found errors, and ends it
+func endQuerySpan(span trace.Span, err error) {
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
@@ -0,0 +1,104 @@
+package db
+
+import (
+	"context"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+var (
+	spanExporterOnce sync.Once
+	spanExporter     *tracetest.InMemoryExporter
+)
+
+// recordSpans installs an in-memory exporter as the global tracer provider and
+// clears it. The package tracer only binds to the first provider ever set, so
+// the exporter is shared by every test.
+func recordSpans(t *testing.T) *tracetest.InMemoryExporter {
+	spanExporterOnce.Do(func() {
+		spanExporter = tracetest.NewInMemoryExporter()
+		otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(spanExporter)))
+	})
+	spanExporter.Reset()
+	t.Cleanup(spanExporter.Reset)
+	return spanExporter
+}
+
+func TestArticleRepository_GetAllSpans(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	author := createTestUser(t, db, "author")
+	createTestArticle(t, db, author, "Traced Article", "go")
+
+	exporter := recordSpans(t)
+	ctx, parent := otel.Tracer("test").Start(context.Background(), "request")
+	_, _, err := NewArticleRepository(db).GetAll(ctx, models.ArticleFilter{Limit: 20}, "")
+	parent.End()
+	if err != nil {
+		t.Fatalf("Failed to get articles: %v", err)
+	}
+
+	spans := exporter.GetSpans()
+	var method tracetest.SpanStub
+	for _, span := range spans {
+		if span.Name == "ArticleRepository.GetAll" {
+			method = span
+		}
+	}
+	if method.Parent.SpanID() != parent.SpanContext().SpanID() {
+		t.Fatalf("Expected an ArticleRepository.GetAll span under the request span, got %d spans", len(spans))
+	}
+
+	// The count, the page and the tags of the page are one query each
+	var statements []string
+	for _, span := range spans {
+		if span.Parent.SpanID() != method.SpanContext.SpanID() {
+			continue
+		}
+		for _, attr := range span.Attributes {
+			if attr.Key == "db.query.text" {
+				statements = append(statements, attr.Value.AsString())
+			}
+		}
+	}
+	if len(statements) != 3 {
+		t.Fatalf("Expected 3 query spans, got %d: %q", len(statements), statements)
+	}
+	if !strings.HasPrefix(statements[0], "SELECT COUNT(*)") || !strings.Contains(statements[2], "article_tags") {
+		t.Errorf("Expected the count, article and tag queries in order, got %q", statements)
+	}
+}
+
+func TestQuerySpan_RecordsErrors(t *testing.T) {
+	db := setupArticleTestDB(t)
+	defer db.Close()
+
+	exporter := recordSpans(t)
+	if _, err := newConn(db).Exec(context.Background(), "INSERT INTO missing_table VALUES (?)", 1); err == nil {
+		t.Fatal("Expected the insert to fail")
+	}
+
+	// Not found is a normal outcome, not a failed query
+	if _, err := NewArticleRepository(db).GetBySlug(context.Background(), "missing", ""); err == nil {
+		t.Fatal("Expected a not found error")
+	}
+
+	spans := exporter.GetSpans()
+	if len(spans) < 2 {
+		t.Fatalf("Expected spans for both statements, got %d", len(spans))
+	}
+	if spans[0].Name != "INSERT" || spans[0].Status.Code != codes.Error {
+		t.Errorf("Expected a failed INSERT span, got %s with status %v", spans[0].Name, spans[0].Status)
+	}
+	if spans[1].Name != "SELECT" || spans[1].Status.Code == codes.Error {
+		t.Errorf("Expected a successful SELECT span for a missing row, got %s with status %v", spans[1].Name, spans[1].Status)
+	}
+}
@@ -1,6 +1,7 @@
 package db
 
 import (
+	"context"
 	"database/sql"
 	"fmt"
 	"time"
@@ -10,34 +11,40 @@ import (
 
 // UserRepository handles user data operations
 type UserRepository struct {
-	db *sql.DB
+	db *conn
 }
 
 // NewUserRepository creates a new user repository
 func NewUserRepository(db *sql.DB) *UserRepository {
-	return &UserRepository{db: db}
+	return &UserRepository{db: newConn(db)}
 }
 
 // Create creates a new user in the database
-func (r *UserRepository) Create(user *models.User) error {
+func (r *UserRepository) Create(ctx context.Context, user *models.User) error {
+	ctx, span := startSpan(ctx, "UserRepository.Create")
+	defer span.End()
+
 	query := `
 		INSERT INTO users (email, username, password_hash, bio, image)
 		VALUES (?, ?, ?, ?, ?)
 		RETURNING id, created_at, updated_at
 	`
 
-	row := r.db.QueryRow(query, user.Email, user.Username, user.PasswordHash, user.Bio, user.Image)
+	row := r.db.QueryRow(ctx, query, user.Email, user.Username, user.PasswordHash, user.Bio, user.Image)
 
 	err := row.Scan(&user.ID, &user.CreatedAt, &user.UpdatedAt)
 	if err != nil {
-		return fmt.Errorf("failed to create user: %w", err)
+		return fmt.Errorf("failed to create user: %w", uniqueViolation(err))
 	}
 
 	return nil
 }
 
 // GetByEmail retrieves a user by email
-func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
+func (r *UserRepository) GetByEmail(ctx context.Context, email string) (*models.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByEmail")
+	defer span.End()
+
 	query := `
 		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
 		FROM users
@@ -45,7 +52,7 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 	`
 
 	var user models.User
-	row := r.db.QueryRow(query, email)
+	row := r.db.QueryRow(ctx, query, email)
 
 	err := row.Scan(
 		&user.ID,
@@ -60,7 +67,7 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, notFoundError("user")
 		}
 		return nil, fmt.Errorf("failed to get user by email: %w", err)
 	}
@@ -69,7 +76,10 @@ func (r *UserRepository) GetByEmail(email string) (*models.User, error) {
 }
 
 // GetByID retrieves a user by ID
-func (r *UserRepository) GetByID(id string) (*models.User, error) {
+func (r *UserRepository) GetByID(ctx context.Context, id string) (*models.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByID")
+	defer span.End()
+
 	query := `
 		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
 		FROM users
@@ -77,7 +87,7 @@ func (r *UserRepository) GetByID(id string) (*models.User, error) {
 	`
 
 	var user models.User
-	row := r.db.QueryRow(query, id)
+	row := r.db.QueryRow(ctx, query, id)
 
 	err := row.Scan(
 		&user.ID,
@@ -92,7 +102,7 @@ func (r *UserRepository) GetByID(id string) (*models.User, error) {
 
 	if err != nil {
 		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("user not found")
+			return nil, notFoundError("user")
 		}
 		return nil, fmt.Errorf("failed to get user by ID: %w", err)
 	}
@@ -100,12 +110,50 @@ func (r *UserRepository) GetByID(id string) (*models.User, error) {
 	return &user, nil
 }
 
+// GetByUsername retrieves a user by username
+func (r *UserRepository) GetByUsername(ctx context.Context, username string) (*models.User, error) {
+	ctx, span := startSpan(ctx, "UserRepository.GetByUsername")
+	defer span.End()
+
+	query := `
+		SELECT id, email, username, password_hash, bio, image, created_at, updated_at
+		FROM users
+		WHERE username = ?
+	`
+
+	var user models.User
+	row := r.db.QueryRow(ctx, query, username)
+
+	err := row.Scan(
+		&user.ID,
+		&user.Email,
+		&user.Username,
+		&user.PasswordHash,
+		&user.Bio,
+		&user.Image,
+		&user.CreatedAt,
+		&user.UpdatedAt,
+	)
+
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, notFoundError("user")
+		}
+		return nil, fmt.Errorf("failed to get user by username: %w", err)
+	}
+
+	return &user, nil
+}
+
 // EmailExists checks if an email already exists in the database
-func (r *UserRepository) EmailExists(email string) (bool, error) {
+func (r *UserRepository) EmailExists(ctx context.Context, email string) (bool, error) {
+	ctx, span := startSpan(ctx, "UserRepository.EmailExists")
+	defer span.End()
+
 	query := `SELECT COUNT(*) FROM users WHERE email = ?`
 
 	var count int
-	err := r.db.QueryRow(query, email).Scan(&count)
+	err := r.db.QueryRow(ctx, query, email).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check email existence: %w", err)
 	}
@@ -114,11 +162,14 @@ func (r *UserRepository) EmailExists(email string) (bool, error) {
 }
 
 // UsernameExists checks if a username already exists in the database
-func (r *UserRepository) UsernameExists(username string) (bool, error) {
+func (r *UserRepository) UsernameExists(ctx context.Context, username string) (bool, error) {
+	ctx, span := startSpan(ctx, "UserRepository.UsernameExists")
+	defer span.End()
+
 	query := `SELECT COUNT(*) FROM users WHERE username = ?`
 
 	var count int
-	err := r.db.QueryRow(query, username).Scan(&count)
+	err := r.db.QueryRow(ctx, query, username).Scan(&count)
 	if err != nil {
 		return false, fmt.Errorf("failed to check username existence: %w", err)
 	}
@@ -127,7 +178,10 @@ func (r *UserRepository) UsernameExists(username string) (bool, error) {
 }
 
 // Update updates user information
-func (r *UserRepository) Update(user *models.User) error {
+func (r *UserRepository) Update(ctx context.Context, user *models.User) error {
+	ctx, span := startSpan(ctx, "UserRepository.Update")
+	defer span.End()
+
 	query := `
 		UPDATE users 
 		SET email = ?, username = ?, password_hash = ?, bio = ?, image = ?, updated_at = ?
@@ -136,7 +190,7 @@ func (r *UserRepository) Update(user *models.User) error {
 
 	user.UpdatedAt = time.Now()
 
-	_, err := r.db.Exec(query,
+	_, err := r.db.Exec(ctx, query,
 		user.Email,
 		user.Username,
 		user.PasswordHash,
@@ -147,7 +201,7 @@ func (r *UserRepository) Update(user *models.User) error {
 	)
 
 	if err != nil {
-		return fmt.Errorf("failed to update user: %w", err)
+		return fmt.Errorf("failed to update user: %w", uniqueViolation(err))
 	}
 
 	return nil
@@ -1,39 +1,16 @@
 package db
 
 import (
+	"context"
 	"database/sql"
+	"errors"
 	"testing"
 
-	_ "github.com/mattn/go-sqlite3"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
 )
 
 func setupTestDB(t *testing.T) *sql.DB {
-	// Create in-memory database for testing
-	db, err := sql.Open("sqlite3", ":memory:")
-	if err != nil {
-		t.Fatalf("Failed to create test database: %v", err)
-	}
-
-	// Create users table
-	createTableSQL := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
-		email TEXT NOT NULL UNIQUE,
-		username TEXT NOT NULL UNIQUE,
-		password_hash TEXT NOT NULL,
-		bio TEXT DEFAULT '',
-		image TEXT DEFAULT '',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	_, err = db.Exec(createTableSQL)
-	if err != nil {
-		t.Fatalf("Failed to create users table: %v", err)
-	}
-
-	return db
+	return openTestDB(t)
 }
 
 func TestUserRepository_Create(t *testing.T) {
@@ -50,7 +27,7 @@ func TestUserRepository_Create(t *testing.T) {
 		Image:        "",
 	}
 
-	err := repo.Create(user)
+	err := repo.Create(context.Background(), user)
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
@@ -74,13 +51,13 @@ func TestUserRepository_GetByEmail(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	err := repo.Create(user)
+	err := repo.Create(context.Background(), user)
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
 
 	// Retrieve by email
-	retrievedUser, err := repo.GetByEmail("test@example.com")
+	retrievedUser, err := repo.GetByEmail(context.Background(), "test@example.com")
 	if err != nil {
 		t.Fatalf("Failed to get user by email: %v", err)
 	}
@@ -107,13 +84,13 @@ func TestUserRepository_GetByID(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	err := repo.Create(user)
+	err := repo.Create(context.Background(), user)
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
 
 	// Retrieve by ID
-	retrievedUser, err := repo.GetByID(user.ID)
+	retrievedUser, err := repo.GetByID(context.Background(), user.ID)
 	if err != nil {
 		t.Fatalf("Failed to get user by ID: %v", err)
 	}
@@ -129,10 +106,35 @@ func TestUserRepository_GetByEmail_NotFound(t *testing.T) {
 
 	repo := NewUserRepository(db)
 
-	_, err := repo.GetByEmail("nonexistent@example.com")
+	_, err := repo.GetByEmail(context.Background(), "nonexistent@example.com")
 	if err == nil {
 		t.Fatal("Expected error for non-existent user, got nil")
 	}
+
+	if !errors.Is(err, ErrNotFound) {
+		t.Errorf("Expected ErrNotFound, got %v", err)
+	}
+}
+
+func TestUserRepository_Create_Conflict(t *testing.T) {
+	db := setupTestDB(t)
+	defer db.Close()
+
+	repo := NewUserRepository(db)
+
+	if err := repo.Create(context.Background(), &models.User{Email: "test@example.com", Username: "first", PasswordHash: "hash"}); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	err := repo.Create(context.Background(), &models.User{Email: "test@example.com", Username: "second", PasswordHash: "hash"})
+	if !errors.Is(err, ErrConflict) {
+		t.Fatalf("Expected ErrConflict, got %v", err)
+	}
+
+	var dbErr *Error
+	if !errors.As(err, &dbErr) || dbErr.Resource != "email" {
+		t.Errorf("Expected conflict on email, got %v", err)
+	}
 }
 
 func TestUserRepository_EmailExists(t *testing.T) {
@@ -148,13 +150,13 @@ func TestUserRepository_EmailExists(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	err := repo.Create(user)
+	err := repo.Create(context.Background(), user)
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
 
 	// Check if email exists
-	exists, err := repo.EmailExists("test@example.com")
+	exists, err := repo.EmailExists(context.Background(), "test@example.com")
 	if err != nil {
 		t.Fatalf("Failed to check email existence: %v", err)
 	}
@@ -164,7 +166,7 @@ func TestUserRepository_EmailExists(t *testing.T) {
 	}
 
 	// Check non-existent email
-	exists, err = repo.EmailExists("nonexistent@example.com")
+	exists, err = repo.EmailExists(context.Background(), "nonexistent@example.com")
 	if err != nil {
 		t.Fatalf("Failed to check email existence: %v", err)
 	}
@@ -187,13 +189,13 @@ func TestUserRepository_UsernameExists(t *testing.T) {
 		PasswordHash: "hashedpassword",
 	}
 
-	err := repo.Create(user)
+	err := repo.Create(context.Background(), user)
 	if err != nil {
 		t.Fatalf("Failed to create user: %v", err)
 	}
 
 	// Check if username exists
-	exists, err := repo.UsernameExists("testuser")
+	exists, err := repo.UsernameExists(context.Background(), "testuser")
 	if err != nil {
 		t.Fatalf("Failed to check username existence: %v", err)
 	}
@@ -203,7 +205,7 @@ func TestUserRepository_UsernameExists(t *testing.T) {
 	}
 
 	// Check non-existent username
-	exists, err = repo.UsernameExists("nonexistentuser")
+	exists, err = repo.UsernameExists(context.Background(), "nonexistentuser")
 	if err != nil {
 		t.Fatalf("Failed to check username existence: %v", err)
 	}
@@ -2,22 +2,26 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
+	"net/url"
 	"strconv"
 	"strings"
 
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/validation"
 )
 
 // ArticleHandler handles article-related HTTP requests
 type ArticleHandler struct {
-	articleRepo *db.ArticleRepository
-	userRepo    *db.UserRepository
+	articleRepo db.ArticleStore
+	userRepo    db.UserStore
 }
 
 // NewArticleHandler creates a new article handler
-func NewArticleHandler(articleRepo *db.ArticleRepository, userRepo *db.UserRepository) *ArticleHandler {
+func NewArticleHandler(articleRepo db.ArticleStore, userRepo db.UserStore) *ArticleHandler {
 	return &ArticleHandler{
 		articleRepo: articleRepo,
 		userRepo:    userRepo,
@@ -26,39 +30,48 @@ func NewArticleHandler(articleRepo *db.ArticleRepository, userRepo *db.UserRepos
 
 // GetArticles handles GET /api/articles
 func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Parse query parameters
 	query := r.URL.Query()
+	limit, offset := parsePagination(r)
 	filter := models.ArticleFilter{
 		Tag:       query.Get("tag"),
 		Author:    query.Get("author"),
 		Favorited: query.Get("favorited"),
-		Limit:     20, // Default limit
-		Offset:    0,  // Default offset
+		Limit:     limit,
+		Offset:    offset,
 	}
 
-	// Parse limit
-	if limitStr := query.Get("limit"); limitStr != "" {
-		if limit, err := strconv.Atoi(limitStr); err == nil && limit > 0 {
-			filter.Limit = limit
-		}
+	// Get articles as seen by the optional viewer
+	articles, total, err := h.articleRepo.GetAll(r.Context(), filter, currentUserID(r))
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch articles", err)
+		return
 	}
 
-	// Parse offset
-	if offsetStr := query.Get("offset"); offsetStr != "" {
-		if offset, err := strconv.Atoi(offsetStr); err == nil && offset >= 0 {
-			filter.Offset = offset
-		}
+	// Return response
+	response := models.ArticlesResponse{
+		Articles:      articles,
+		ArticlesCount: total,
 	}
 
-	// Get articles
-	articles, total, err := h.articleRepo.GetAll(filter)
+	WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// GetFeed handles GET /api/articles/feed
+func (h *ArticleHandler) GetFeed(w http.ResponseWriter, r *http.Request) {
+	// Get user from auth middleware
+	userID := currentUserID(r)
+	if userID == "" {
+		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
+		return
+	}
+
+	limit, offset := parsePagination(r)
+
+	// Get articles from followed authors
+	articles, total, err := h.articleRepo.GetFeed(r.Context(), userID, limit, offset)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch articles")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch feed", err)
 		return
 	}
 
@@ -73,26 +86,26 @@ func (h *ArticleHandler) GetArticles(w http.ResponseWriter, r *http.Request) {
 
 // GetArticle handles GET /api/articles/:slug
 func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Extract slug from URL path
-	slug := h.extractSlugFromPath(r.URL.Path)
+	slug := r.PathValue("slug")
 	if slug == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
 		return
 	}
 
-	// Get article
-	article, err := h.articleRepo.GetBySlug(slug)
+	// Get article as seen by the optional viewer
+	article, err := h.articleRepo.GetBySlug(r.Context(), slug, currentUserID(r))
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
+		if errors.Is(err, db.ErrNotFound) {
+			// Permanently redirect previous slugs of a renamed article
+			if currentSlug, err := h.articleRepo.ResolveSlug(r.Context(), slug); err == nil {
+				http.Redirect(w, r, "/api/articles/"+url.PathEscape(currentSlug), http.StatusMovedPermanently)
+				return
+			}
 			WriteErrorResponse(w, http.StatusNotFound, "article", "Article not found")
 			return
 		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article", err)
 		return
 	}
 
@@ -111,13 +124,8 @@ func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 
 // CreateArticle handles POST /api/articles
 func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Get user from auth middleware
-	userID := r.Header.Get("X-User-ID")
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
 		return
@@ -130,24 +138,27 @@ func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Validate required fields
-	if req.Article.Title == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "title", "Title is required")
-		return
+	// Validate every field before reporting
+	v := validation.New()
+	if v.Required("title", req.Article.Title) {
+		v.MaxLength("title", req.Article.Title, validation.TitleMaxLength)
 	}
-	if req.Article.Description == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "description", "Description is required")
-		return
+	if v.Required("description", req.Article.Description) {
+		v.MaxLength("description", req.Article.Description, validation.DescriptionMaxLength)
+	}
+	if v.Required("body", req.Article.Body) {
+		v.MaxLength("body", req.Article.Body, validation.BodyMaxLength)
 	}
-	if req.Article.Body == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "body", "Body is required")
+	v.Tags("tagList", req.Article.TagList)
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
 		return
 	}
 
 	// Get user info
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
@@ -162,11 +173,13 @@ func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 		},
 	}
 
-	if err := h.articleRepo.Create(article); err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create article")
+	if err := h.articleRepo.Create(r.Context(), article); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create article", err)
 		return
 	}
 
+	metrics.ArticlesCreated.Inc()
+
 	// Return response
 	response := article.ToResponse(user)
 	WriteJSONResponse(w, http.StatusCreated, response)
@@ -174,40 +187,37 @@ func (h *ArticleHandler) CreateArticle(w http.ResponseWriter, r *http.Request) {
 
 // UpdateArticle handles PUT /api/articles/:slug
 func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPut {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Get user from auth middleware
-	userID := r.Header.Get("X-User-ID")
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
 		return
 	}
 
 	// Extract slug from URL path
-	slug := h.extractSlugFromPath(r.URL.Path)
+	slug := r.PathValue("slug")
 	if slug == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
 		return
 	}
 
+	// Previous slugs of a renamed article resolve to its current slug
+	slug, ok := h.resolveSlug(w, r, slug)
+	if !ok {
+		return
+	}
+
 	// Check if article exists and user is the author
-	existingArticle, err := h.articleRepo.GetBySlug(slug)
+	existingArticle, err := h.articleRepo.GetBySlug(r.Context(), slug, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			WriteErrorResponse(w, http.StatusNotFound, "article", "Article not found")
-			return
-		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article", err)
 		return
 	}
 
 	// Get current user
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
@@ -224,6 +234,30 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	// Trim the provided fields, a field that is present must not be blank
+	for _, field := range []*string{req.Article.Title, req.Article.Description, req.Article.Body} {
+		if field != nil {
+			*field = strings.TrimSpace(*field)
+		}
+	}
+
+	// Validate the provided fields, omitted fields are left unchanged
+	v := validation.New()
+	if req.Article.Title != nil && v.Required("title", *req.Article.Title) {
+		v.MaxLength("title", *req.Article.Title, validation.TitleMaxLength)
+	}
+	if req.Article.Description != nil && v.Required("description", *req.Article.Description) {
+		v.MaxLength("description", *req.Article.Description, validation.DescriptionMaxLength)
+	}
+	if req.Article.Body != nil && v.Required("body", *req.Article.Body) {
+		v.MaxLength("body", *req.Article.Body, validation.BodyMaxLength)
+	}
+	v.Tags("tagList", req.Article.TagList)
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
+		return
+	}
+
 	// Create update article with only provided fields
 	updateArticle := &models.Article{}
 	if req.Article.Title != nil {
@@ -240,22 +274,16 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Update article
-	if err := h.articleRepo.Update(slug, updateArticle); err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to update article")
+	if err := h.articleRepo.Update(r.Context(), slug, updateArticle); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to update article", err)
 		return
 	}
 
-	// Get updated article
-	updatedArticle, err := h.articleRepo.GetBySlug(updateArticle.Slug)
+	// Get updated article, the repository sets its current slug
+	updatedArticle, err := h.articleRepo.GetBySlug(r.Context(), updateArticle.Slug, userID)
 	if err != nil {
-		// Fallback to original slug if new slug is not set
-		if updateArticle.Slug == "" {
-			updatedArticle, err = h.articleRepo.GetBySlug(slug)
-		}
-		if err != nil {
-			WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch updated article")
-			return
-		}
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch updated article", err)
+		return
 	}
 
 	// Return response
@@ -265,40 +293,37 @@ func (h *ArticleHandler) UpdateArticle(w http.ResponseWriter, r *http.Request) {
 
 // DeleteArticle handles DELETE /api/articles/:slug
 func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Get user from auth middleware
-	userID := r.Header.Get("X-User-ID")
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
 		return
 	}
 
 	// Extract slug from URL path
-	slug := h.extractSlugFromPath(r.URL.Path)
+	slug := r.PathValue("slug")
 	if slug == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
 		return
 	}
 
+	// Previous slugs of a renamed article resolve to its current slug
+	slug, ok := h.resolveSlug(w, r, slug)
+	if !ok {
+		return
+	}
+
 	// Check if article exists and user is the author
-	existingArticle, err := h.articleRepo.GetBySlug(slug)
+	existingArticle, err := h.articleRepo.GetBySlug(r.Context(), slug, userID)
 	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			WriteErrorResponse(w, http.StatusNotFound, "article", "Article not found")
-			return
-		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article", err)
 		return
 	}
 
 	// Get current user
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
@@ -309,8 +334,8 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Delete article
-	if err := h.articleRepo.Delete(slug); err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to delete article")
+	if err := h.articleRepo.Delete(r.Context(), slug); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to delete article", err)
 		return
 	}
 
@@ -322,15 +347,98 @@ func (h *ArticleHandler) DeleteArticle(w http.ResponseWriter, r *http.Request) {
 	}
 }
 
-// Helper method to extract slug from URL path
-func (h *ArticleHandler) extractSlugFromPath(path string) string {
-	// Expected paths: /api/articles/{slug} or /api/articles/{slug}/comments
-	parts := strings.Split(strings.Trim(path, "/"), "/")
+// parsePagination parses the limit and offset query parameters, falling back to
+// a limit of 20 and an offset of 0 when they are missing or invalid
+func parsePagination(r *http.Request) (int, int) {
+	query := r.URL.Query()
+	limit, offset := 20, 0
+
+	// Parse limit
+	if limitStr := query.Get("limit"); limitStr != "" {
+		if parsed, err := strconv.Atoi(limitStr); err == nil && parsed > 0 {
+			limit = parsed
+		}
+	}
+
+	// Parse offset
+	if offsetStr := query.Get("offset"); offsetStr != "" {
+		if parsed, err := strconv.Atoi(offsetStr); err == nil && parsed >= 0 {
+			offset = parsed
+		}
+	}
+
+	return limit, offset
+}
+
+// FavoriteArticle handles POST /api/articles/:slug/favorite
+func (h *ArticleHandler) FavoriteArticle(w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(w, r, true)
+}
+
+// UnfavoriteArticle handles DELETE /api/articles/:slug/favorite
+func (h *ArticleHandler) UnfavoriteArticle(w http.ResponseWriter, r *http.Request) {
+	h.setFavorite(w, r, false)
+}
 
-	// Should be at least ["api", "articles", "slug"]
-	if len(parts) < 3 || parts[0] != "api" || parts[1] != "articles" {
-		return ""
+// setFavorite favorites or unfavorites the article in the path on behalf of the authenticated user
+func (h *ArticleHandler) setFavorite(w http.ResponseWriter, r *http.Request, favorite bool) {
+	// Get user from auth middleware
+	userID := currentUserID(r)
+	if userID == "" {
+		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
+		return
+	}
+
+	// Extract slug from URL path
+	slug := r.PathValue("slug")
+	if slug == "" {
+		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
+		return
+	}
+
+	// Previous slugs of a renamed article resolve to its current slug
+	slug, ok := h.resolveSlug(w, r, slug)
+	if !ok {
+		return
+	}
+
+	var err error
+	if favorite {
+		err = h.articleRepo.Favorite(r.Context(), slug, userID)
+	} else {
+		err = h.articleRepo.Unfavorite(r.Context(), slug, userID)
+	}
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to update favorite", err)
+		return
+	}
+
+	// Get article with the updated favorite state
+	article, err := h.articleRepo.GetBySlug(r.Context(), slug, userID)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article", err)
+		return
+	}
+
+	author := &models.User{
+		Username: article.Author.Username,
+		Bio:      article.Author.Bio,
+		Image:    article.Author.Image,
+	}
+
+	// Return response
+	response := article.ToResponse(author)
+	WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// resolveSlug maps a current or previous slug to the article's current slug,
+// writing an error response when the article does not exist
+func (h *ArticleHandler) resolveSlug(w http.ResponseWriter, r *http.Request, slug string) (string, bool) {
+	currentSlug, err := h.articleRepo.ResolveSlug(r.Context(), slug)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch article", err)
+		return "", false
 	}
 
-	return parts[2]
+	return currentSlug, true
 }
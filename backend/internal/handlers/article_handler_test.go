@@ -0,0 +1,191 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db/memory"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+// setupTestArticleHandlers returns article and comment handlers backed by an
+// in-memory store, plus a token for each of the given users
+func setupTestArticleHandlers(t *testing.T, usernames ...string) (*ArticleHandler, *CommentHandler, map[string]string) {
+	store := memory.New()
+	tokens := make(map[string]string, len(usernames))
+	for _, username := range usernames {
+		user := &models.User{Email: username + "@example.com", Username: username, PasswordHash: "hash"}
+		if err := store.Users().Create(context.Background(), user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+
+		token, err := testTokens.GenerateToken(user.ID, user.Email)
+		if err != nil {
+			t.Fatalf("Failed to generate token: %v", err)
+		}
+		tokens[username] = token
+	}
+
+	articleHandler := NewArticleHandler(store.Articles(), store.Users())
+	commentHandler := NewCommentHandler(store.Comments(), store.Users())
+	return articleHandler, commentHandler, tokens
+}
+
+func createTestArticleRequest(t *testing.T, handler *ArticleHandler, token, title string) models.Article {
+	body := `{"article":{"title":"` + title + `","description":"Description","body":"Body","tagList":["go"]}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/articles", strings.NewReader(body))
+	req.Header.Set("Authorization", "Token "+token)
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.CreateArticle)(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Failed to create article: status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var response models.ArticleResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode article response: %v", err)
+	}
+	return response.Article
+}
+
+func TestArticleHandler_CreateAndGet(t *testing.T) {
+	handler, _, tokens := setupTestArticleHandlers(t, "alice")
+
+	created := createTestArticleRequest(t, handler, tokens["alice"], "Hello World")
+	if created.Slug != "hello-world" || created.Author.Username != "alice" {
+		t.Fatalf("Unexpected created article: %+v", created)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/hello-world", nil)
+	req.SetPathValue("slug", "hello-world")
+	rr := httptest.NewRecorder()
+	OptionalAuthMiddleware(testTokens, handler.GetArticle)(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+
+	var response models.ArticleResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode article response: %v", err)
+	}
+	if response.Article.Title != "Hello World" || len(response.Article.TagList) != 1 {
+		t.Errorf("Unexpected article: %+v", response.Article)
+	}
+}
+
+func TestArticleHandler_GetArticle_RedirectsPreviousSlug(t *testing.T) {
+	handler, _, tokens := setupTestArticleHandlers(t, "alice")
+	createTestArticleRequest(t, handler, tokens["alice"], "Old Title")
+
+	req := httptest.NewRequest(http.MethodPut, "/api/articles/old-title", strings.NewReader(`{"article":{"title":"New Title"}}`))
+	req.SetPathValue("slug", "old-title")
+	req.Header.Set("Authorization", "Token "+tokens["alice"])
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.UpdateArticle)(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Failed to update article: status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	req = httptest.NewRequest(http.MethodGet, "/api/articles/old-title", nil)
+	req.SetPathValue("slug", "old-title")
+	rr = httptest.NewRecorder()
+	OptionalAuthMiddleware(testTokens, handler.GetArticle)(rr, req)
+
+	if rr.Code != http.StatusMovedPermanently {
+		t.Fatalf("Expected status code %d, got %d", http.StatusMovedPermanently, rr.Code)
+	}
+	if location := rr.Header().Get("Location"); location != "/api/articles/new-title" {
+		t.Errorf("Expected redirect to /api/articles/new-title, got %q", location)
+	}
+}
+
+func TestArticleHandler_UpdateArticle_BlankFields(t *testing.T) {
+	handler, _, tokens := setupTestArticleHandlers(t, "alice")
+	article := createTestArticleRequest(t, handler, tokens["alice"], "Title")
+
+	update := func(body string) *httptest.ResponseRecorder {
+		req := httptest.NewRequest(http.MethodPut, "/api/articles/"+article.Slug, strings.NewReader(body))
+		req.SetPathValue("slug", article.Slug)
+		req.Header.Set("Authorization", "Token "+tokens["alice"])
+		rr := httptest.NewRecorder()
+		AuthMiddleware(testTokens, handler.UpdateArticle)(rr, req)
+		return rr
+	}
+
+	tests := map[string]string{
+		"title":       `{"article":{"title":"   "}}`,
+		"description": `{"article":{"description":""}}`,
+		"body":        `{"article":{"body":"\n\t"}}`,
+	}
+
+	for field, body := range tests {
+		t.Run(field, func(t *testing.T) {
+			rr := update(body)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Errors[field]) != 1 {
+				t.Errorf("Expected a %s error, got %v", field, response.Errors)
+			}
+		})
+	}
+
+	// Present fields are trimmed, omitted fields are left unchanged
+	rr := update(`{"article":{"title":"  New Title  "}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Failed to update article: status %d: %s", rr.Code, rr.Body.String())
+	}
+
+	var response models.ArticleResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode article response: %v", err)
+	}
+	if response.Article.Title != "New Title" || response.Article.Slug != "new-title" || response.Article.Body != "Body" {
+		t.Errorf("Unexpected article: %+v", response.Article)
+	}
+}
+
+func TestCommentHandler_DeleteComment_Forbidden(t *testing.T) {
+	articleHandler, commentHandler, tokens := setupTestArticleHandlers(t, "alice", "bob")
+	article := createTestArticleRequest(t, articleHandler, tokens["alice"], "Title")
+
+	req := httptest.NewRequest(http.MethodPost, "/api/articles/"+article.Slug+"/comments", strings.NewReader(`{"comment":{"body":"Nice"}}`))
+	req.SetPathValue("slug", article.Slug)
+	req.Header.Set("Authorization", "Token "+tokens["alice"])
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, commentHandler.CreateComment)(rr, req)
+
+	var response models.CommentResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode comment response: %v", err)
+	}
+
+	deleteComment := func(token string) int {
+		req := httptest.NewRequest(http.MethodDelete, "/api/articles/"+article.Slug+"/comments/"+response.Comment.ID, nil)
+		req.SetPathValue("slug", article.Slug)
+		req.SetPathValue("id", response.Comment.ID)
+		req.Header.Set("Authorization", "Token "+token)
+		rr := httptest.NewRecorder()
+		AuthMiddleware(testTokens, commentHandler.DeleteComment)(rr, req)
+		return rr.Code
+	}
+
+	if code := deleteComment(tokens["bob"]); code != http.StatusForbidden {
+		t.Errorf("Expected status code %d for another user, got %d", http.StatusForbidden, code)
+	}
+	if code := deleteComment(tokens["alice"]); code != http.StatusOK {
+		t.Errorf("Expected status code %d for the author, got %d", http.StatusOK, code)
+	}
+}
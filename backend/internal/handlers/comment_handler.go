@@ -3,20 +3,21 @@ package handlers
 import (
 	"encoding/json"
 	"net/http"
-	"strings"
 
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/validation"
 )
 
 // CommentHandler handles comment-related HTTP requests
 type CommentHandler struct {
-	commentRepo *db.CommentRepository
-	userRepo    *db.UserRepository
+	commentRepo db.CommentStore
+	userRepo    db.UserStore
 }
 
 // NewCommentHandler creates a new comment handler
-func NewCommentHandler(commentRepo *db.CommentRepository, userRepo *db.UserRepository) *CommentHandler {
+func NewCommentHandler(commentRepo db.CommentStore, userRepo db.UserStore) *CommentHandler {
 	return &CommentHandler{
 		commentRepo: commentRepo,
 		userRepo:    userRepo,
@@ -25,26 +26,17 @@ func NewCommentHandler(commentRepo *db.CommentRepository, userRepo *db.UserRepos
 
 // GetComments handles GET /api/articles/:slug/comments
 func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Extract slug from URL path
-	slug := h.extractSlugFromPath(r.URL.Path)
+	slug := r.PathValue("slug")
 	if slug == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
 		return
 	}
 
-	// Get comments
-	comments, err := h.commentRepo.GetByArticleSlug(slug)
+	// Get comments as seen by the optional viewer
+	comments, err := h.commentRepo.GetByArticleSlug(r.Context(), slug, currentUserID(r))
 	if err != nil {
-		if strings.Contains(err.Error(), "article not found") {
-			WriteErrorResponse(w, http.StatusNotFound, "article", "Article not found")
-			return
-		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch comments")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch comments", err)
 		return
 	}
 
@@ -58,20 +50,15 @@ func (h *CommentHandler) GetComments(w http.ResponseWriter, r *http.Request) {
 
 // CreateComment handles POST /api/articles/:slug/comments
 func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Get user from auth middleware
-	userID := r.Header.Get("X-User-ID")
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
 		return
 	}
 
 	// Extract slug from URL path
-	slug := h.extractSlugFromPath(r.URL.Path)
+	slug := r.PathValue("slug")
 	if slug == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "slug", "Invalid slug")
 		return
@@ -85,15 +72,19 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if req.Comment.Body == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "body", "Body is required")
+	v := validation.New()
+	if v.Required("body", req.Comment.Body) {
+		v.MaxLength("body", req.Comment.Body, validation.CommentMaxLength)
+	}
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
 		return
 	}
 
 	// Get user info
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
@@ -102,12 +93,8 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		Body: req.Comment.Body,
 	}
 
-	if err := h.commentRepo.Create(comment, slug, userID); err != nil {
-		if strings.Contains(err.Error(), "article not found") {
-			WriteErrorResponse(w, http.StatusNotFound, "article", "Article not found")
-			return
-		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create comment")
+	if err := h.commentRepo.Create(r.Context(), comment, slug, userID); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create comment", err)
 		return
 	}
 
@@ -116,9 +103,11 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 		Username:  user.Username,
 		Bio:       user.Bio,
 		Image:     user.Image,
-		Following: false, // TODO: Implement following logic
+		Following: false, // Users cannot follow themselves
 	}
 
+	metrics.CommentsPosted.Inc()
+
 	// Return response
 	response := comment.ToResponse(user)
 	WriteJSONResponse(w, http.StatusCreated, response)
@@ -126,52 +115,23 @@ func (h *CommentHandler) CreateComment(w http.ResponseWriter, r *http.Request) {
 
 // DeleteComment handles DELETE /api/articles/:slug/comments/:id
 func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodDelete {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	// Get user from auth middleware
-	userID := r.Header.Get("X-User-ID")
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
 		return
 	}
 
 	// Extract slug and comment ID from URL path
-	slug, commentID := h.extractSlugAndCommentIDFromPath(r.URL.Path)
+	slug, commentID := r.PathValue("slug"), r.PathValue("id")
 	if slug == "" || commentID == "" {
 		WriteErrorResponse(w, http.StatusBadRequest, "path", "Invalid slug or comment ID")
 		return
 	}
 
-	// Check if comment exists
-	comment, err := h.commentRepo.GetByID(commentID)
-	if err != nil {
-		if strings.Contains(err.Error(), "not found") {
-			WriteErrorResponse(w, http.StatusNotFound, "comment", "Comment not found")
-			return
-		}
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch comment")
-		return
-	}
-
-	// Get current user
-	user, err := h.userRepo.GetByID(userID)
-	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user")
-		return
-	}
-
-	// Check if user is the author of the comment
-	if comment.Author.Username != user.Username {
-		WriteErrorResponse(w, http.StatusForbidden, "permission", "You can only delete your own comments")
-		return
-	}
-
-	// Delete comment
-	if err := h.commentRepo.Delete(commentID); err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to delete comment")
+	// Delete comment, the repository rejects comments written by other users
+	if err := h.commentRepo.Delete(r.Context(), commentID, userID); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to delete comment", err)
 		return
 	}
 
@@ -182,31 +142,3 @@ func (h *CommentHandler) DeleteComment(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 }
-
-// Helper methods
-
-// extractSlugFromPath extracts slug from /api/articles/{slug}/comments path
-func (h *CommentHandler) extractSlugFromPath(path string) string {
-	// Expected path: /api/articles/{slug}/comments
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-
-	// Should be ["api", "articles", "slug", "comments"]
-	if len(parts) != 4 || parts[0] != "api" || parts[1] != "articles" || parts[3] != "comments" {
-		return ""
-	}
-
-	return parts[2]
-}
-
-// extractSlugAndCommentIDFromPath extracts slug and comment ID from /api/articles/{slug}/comments/{id} path
-func (h *CommentHandler) extractSlugAndCommentIDFromPath(path string) (string, string) {
-	// Expected path: /api/articles/{slug}/comments/{id}
-	parts := strings.Split(strings.Trim(path, "/"), "/")
-
-	// Should be ["api", "articles", "slug", "comments", "id"]
-	if len(parts) != 5 || parts[0] != "api" || parts[1] != "articles" || parts[3] != "comments" {
-		return "", ""
-	}
-
-	return parts[2], parts[4]
-}
@@ -2,10 +2,24 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
 	"net/http"
+	"runtime/debug"
 	"strings"
+	"time"
 
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/logging"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/validation"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // ErrorResponse represents an error response
@@ -22,8 +36,19 @@ func NewErrorResponse(field, message string) ErrorResponse {
 	}
 }
 
-// WriteErrorResponse writes an error response to the HTTP response writer
-func WriteErrorResponse(w http.ResponseWriter, statusCode int, field, message string) {
+// WriteErrorResponse writes an error response to the HTTP response writer.
+// When the error returned by a repository is passed, its kind decides the
+// response instead of statusCode, field and message:
+//   - db.ErrNotFound is a 404 for the resource named by the error
+//   - db.ErrConflict is a 422 on the duplicate column, like the validation
+//     errors of the uniqueness checks it backs up
+//   - db.ErrForbidden is a 403
+//   - db.ErrTimeout is a 503 asking the client to retry
+func WriteErrorResponse(w http.ResponseWriter, statusCode int, field, message string, cause ...error) {
+	if len(cause) > 0 && cause[0] != nil {
+		statusCode, field, message = repositoryErrorResponse(w, cause[0], statusCode, field, message)
+	}
+
 	w.Header().Set("Content-Type", "application/json")
 	w.WriteHeader(statusCode)
 
@@ -37,6 +62,36 @@ func WriteErrorResponse(w http.ResponseWriter, statusCode int, field, message st
 	}
 }
 
+// repositoryErrorResponse maps a repository error to the status, field and
+// message of its response. Errors of no known kind keep the given ones.
+func repositoryErrorResponse(w http.ResponseWriter, err error, statusCode int, field, message string) (int, string, string) {
+	resource := "resource"
+	var dbErr *db.Error
+	if errors.As(err, &dbErr) && dbErr.Resource != "" {
+		resource = dbErr.Resource
+	}
+	name := strings.ToUpper(resource[:1]) + resource[1:]
+
+	switch {
+	case errors.Is(err, db.ErrNotFound):
+		return http.StatusNotFound, resource, name + " not found"
+	case errors.Is(err, db.ErrConflict):
+		return http.StatusUnprocessableEntity, resource, name + " already exists"
+	case errors.Is(err, db.ErrForbidden):
+		return http.StatusForbidden, "permission", "You can only modify your own " + resource + "s"
+	case errors.Is(err, db.ErrTimeout):
+		w.Header().Set("Retry-After", "1")
+		return http.StatusServiceUnavailable, "database", "Database is busy, please retry"
+	default:
+		return statusCode, field, message
+	}
+}
+
+// WriteValidationErrors writes every validation violation in a single 422 response
+func WriteValidationErrors(w http.ResponseWriter, errs validation.Errors) {
+	WriteJSONResponse(w, http.StatusUnprocessableEntity, ErrorResponse{Errors: errs})
+}
+
 // WriteJSONResponse writes a JSON response to the HTTP response writer
 func WriteJSONResponse(w http.ResponseWriter, statusCode int, data interface{}) {
 	w.Header().Set("Content-Type", "application/json")
@@ -51,7 +106,7 @@ func WriteJSONResponse(w http.ResponseWriter, statusCode int, data interface{})
 }
 
 // AuthMiddleware validates JWT tokens for protected routes
-func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
+func AuthMiddleware(tokens *auth.TokenManager, next http.HandlerFunc) http.HandlerFunc {
 	return func(w http.ResponseWriter, r *http.Request) {
 		// Get Authorization header
 		authHeader := r.Header.Get("Authorization")
@@ -74,26 +129,111 @@ func AuthMiddleware(next http.HandlerFunc) http.HandlerFunc {
 		}
 
 		// Validate token
-		claims, err := auth.ValidateToken(tokenString)
+		claims, err := tokens.ValidateToken(tokenString)
 		if err != nil {
 			WriteErrorResponse(w, http.StatusUnauthorized, "token", "Invalid token")
 			return
 		}
 
-		// Add user ID to request context
-		r.Header.Set("X-User-ID", claims.UserID)
-		r.Header.Set("X-User-Email", claims.Email)
+		// Add user to request context
+		ctx := auth.WithCurrentUser(r.Context(), auth.User{ID: claims.UserID, Email: claims.Email})
+		logging.SetUser(ctx, claims.UserID)
+
+		next.ServeHTTP(w, r.WithContext(ctx))
+	}
+}
+
+// OptionalAuthMiddleware attaches the viewer identity for public routes when a valid
+// token is present. Missing, malformed or invalid tokens are ignored and the request
+// continues anonymously.
+func OptionalAuthMiddleware(tokens *auth.TokenManager, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		authHeader := r.Header.Get("Authorization")
+		if strings.HasPrefix(authHeader, "Token ") {
+			if claims, err := tokens.ValidateToken(strings.TrimPrefix(authHeader, "Token ")); err == nil {
+				ctx := auth.WithCurrentUser(r.Context(), auth.User{ID: claims.UserID, Email: claims.Email})
+				logging.SetUser(ctx, claims.UserID)
+				r = r.WithContext(ctx)
+			}
+		}
 
 		next.ServeHTTP(w, r)
 	}
 }
 
+// StripIdentityHeadersMiddleware removes legacy X-User-* headers sent by clients so
+// that no handler can be tricked into trusting a spoofed identity. The authenticated
+// user is only ever available through auth.CurrentUser.
+func StripIdentityHeadersMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		for name := range r.Header {
+			if strings.HasPrefix(name, "X-User-") {
+				r.Header.Del(name)
+			}
+		}
+
+		next.ServeHTTP(w, r)
+	})
+}
+
+// currentUserID returns the ID of the authenticated user, or an empty string for anonymous requests
+func currentUserID(r *http.Request) string {
+	user, ok := auth.CurrentUser(r.Context())
+	if !ok {
+		return ""
+	}
+	return user.ID
+}
+
+// RouteErrorMiddleware renders the router's own 404 and 405 responses in the
+// RealWorld error format. The Allow header set by the router for 405s is kept.
+func RouteErrorMiddleware(mux *http.ServeMux) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Requests matching a route are served untouched
+		if _, pattern := mux.Handler(r); pattern != "" {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		mux.ServeHTTP(&routeErrorWriter{ResponseWriter: w}, r)
+	})
+}
+
+// routeErrorWriter replaces the plain text body of router errors with a JSON error
+type routeErrorWriter struct {
+	http.ResponseWriter
+	replaced bool
+}
+
+// WriteHeader writes a JSON error for 404 and 405 responses
+func (w *routeErrorWriter) WriteHeader(statusCode int) {
+	switch statusCode {
+	case http.StatusNotFound:
+		w.replaced = true
+		WriteErrorResponse(w.ResponseWriter, statusCode, "path", "Endpoint not found")
+	case http.StatusMethodNotAllowed:
+		w.replaced = true
+		WriteErrorResponse(w.ResponseWriter, statusCode, "method", "Method not allowed")
+	default:
+		w.ResponseWriter.WriteHeader(statusCode)
+	}
+}
+
+// Write discards the router's plain text body once a JSON error has been written
+func (w *routeErrorWriter) Write(b []byte) (int, error) {
+	if w.replaced {
+		return len(b), nil
+	}
+	return w.ResponseWriter.Write(b)
+}
+
 // CORSMiddleware handles CORS headers
 func CORSMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		w.Header().Set("Access-Control-Allow-Origin", "*")
 		w.Header().Set("Access-Control-Allow-Methods", "GET, POST, PUT, DELETE, OPTIONS")
-		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Request-ID, traceparent, tracestate")
+		w.Header().Set("Access-Control-Expose-Headers", "X-Request-ID")
 
 		if r.Method == "OPTIONS" {
 			w.WriteHeader(http.StatusOK)
@@ -103,3 +243,228 @@ func CORSMiddleware(next http.Handler) http.Handler {
 		next.ServeHTTP(w, r)
 	})
 }
+
+// RequestLoggingMiddleware assigns every request an ID and writes an access log
+// entry once it has been served. An incoming X-Request-ID, e.g. from a load
+// balancer, is reused when valid so logs can be correlated across services. The ID
+// is echoed in the X-Request-ID response header and added to every record logged
+// through logging.FromContext during the request, along with the trace_id when
+// the request is traced.
+func RequestLoggingMiddleware(logger *slog.Logger, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		requestID := r.Header.Get("X-Request-ID")
+		if !logging.ValidRequestID(requestID) {
+			requestID = logging.NewRequestID()
+		}
+		w.Header().Set("X-Request-ID", requestID)
+
+		requestLogger := logger
+		if sc := trace.SpanContextFromContext(r.Context()); sc.IsValid() {
+			requestLogger = logger.With("trace_id", sc.TraceID().String())
+		}
+
+		ctx := logging.NewContext(r.Context(), requestLogger, requestID)
+		rw := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		// Health checks arrive every few seconds and are only logged at debug level
+		level := slog.LevelInfo
+		if r.URL.Path == "/health" {
+			level = slog.LevelDebug
+		}
+
+		logging.FromContext(ctx).LogAttrs(ctx, level, "request",
+			slog.String("method", r.Method),
+			slog.String("path", r.URL.Path),
+			slog.Int("status", rw.Status()),
+			slog.Int64("bytes", rw.bytes),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("user_id", logging.User(ctx)),
+			slog.String("remote_addr", r.RemoteAddr),
+			slog.String("user_agent", r.UserAgent()),
+		)
+	})
+}
+
+// MetricsMiddleware records the count, status and latency of every request under
+// the mux route pattern it matches, e.g. /api/articles/{slug}. Requests matching no
+// route share the "unmatched" route so that scanners cannot create new series.
+func MetricsMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+
+		route := routePattern(mux, r)
+
+		rw := &statusRecorder{ResponseWriter: w}
+		defer func() {
+			status := rw.Status()
+			// A panic is answered with a 500 by RecoveryMiddleware further out
+			rec := recover()
+			if rec != nil && rw.status == 0 {
+				status = http.StatusInternalServerError
+			}
+
+			metrics.ObserveRequest(knownMethod(r.Method), route, status, time.Since(start))
+
+			if rec != nil {
+				panic(rec)
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// routePattern returns the path pattern of the mux route matching r, e.g.
+// /api/articles/{slug}, or "unmatched"
+func routePattern(mux *http.ServeMux, r *http.Request) string {
+	_, pattern := mux.Handler(r)
+	if pattern == "" {
+		return "unmatched"
+	}
+
+	// The method of "GET /api/tags" is recorded separately
+	if _, path, ok := strings.Cut(pattern, " "); ok {
+		return path
+	}
+	return pattern
+}
+
+// TracingMiddleware starts a server span for every request, continuing the trace
+// of an incoming W3C traceparent header. The span is named after the route
+// pattern, e.g. "GET /api/articles/{slug}", and is marked as failed for 5xx
+// responses. Repository spans become its children through the request context.
+func TracingMiddleware(mux *http.ServeMux, next http.Handler) http.Handler {
+	tracer := otel.Tracer("github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/handlers")
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		ctx := otel.GetTextMapPropagator().Extract(r.Context(), propagation.HeaderCarrier(r.Header))
+
+		route := routePattern(mux, r)
+		method := knownMethod(r.Method)
+		ctx, span := tracer.Start(ctx, method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(method),
+				semconv.HTTPRoute(route),
+				semconv.URLPath(r.URL.Path),
+				semconv.UserAgentOriginal(r.UserAgent()),
+			),
+		)
+		defer span.End()
+
+		rw := &statusRecorder{ResponseWriter: w}
+		next.ServeHTTP(rw, r.WithContext(ctx))
+
+		status := rw.Status()
+		span.SetAttributes(semconv.HTTPResponseStatusCode(status))
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	})
+}
+
+// knownMethod returns method for the standard methods and OTHER for anything a
+// client made up, keeping metric labels and span names bounded
+func knownMethod(method string) string {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost, http.MethodPut,
+		http.MethodPatch, http.MethodDelete, http.MethodOptions:
+		return method
+	default:
+		return "OTHER"
+	}
+}
+
+// ErrorReporter receives every panic recovered by RecoveryMiddleware, e.g. to
+// forward it to an error tracking service. The request ID is available through
+// logging.RequestID(r.Context()).
+type ErrorReporter func(r *http.Request, err error, stack []byte)
+
+// RecoveryMiddleware turns a panic in a handler into a 500 response in the
+// RealWorld error format instead of a dropped connection. The panic is logged
+// with its stack and passed to every reporter. When the handler has already
+// started the response the status can no longer change and the body is cut short.
+func RecoveryMiddleware(next http.Handler, reporters ...ErrorReporter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rw := &statusRecorder{ResponseWriter: w}
+
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+			// The server relies on this panic to abort a response silently
+			if rec == http.ErrAbortHandler {
+				panic(rec)
+			}
+
+			err := panicError(rec)
+			stack := debug.Stack()
+			logging.FromContext(r.Context()).Error("Recovered from panic",
+				"error", err,
+				"method", r.Method,
+				"path", r.URL.Path,
+				"stack", string(stack),
+			)
+
+			for _, report := range reporters {
+				report(r, err, stack)
+			}
+
+			if rw.status == 0 {
+				WriteErrorResponse(rw, http.StatusInternalServerError, "system", "Internal server error")
+			}
+		}()
+
+		next.ServeHTTP(rw, r)
+	})
+}
+
+// panicError converts a recovered value to an error, keeping errors unwrappable
+func panicError(rec interface{}) error {
+	if err, ok := rec.(error); ok {
+		return fmt.Errorf("panic: %w", err)
+	}
+	return fmt.Errorf("panic: %v", rec)
+}
+
+// statusRecorder records the status code and body size written by a handler
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int64
+}
+
+// WriteHeader records the status code
+func (w *statusRecorder) WriteHeader(statusCode int) {
+	if w.status == 0 {
+		w.status = statusCode
+	}
+	w.ResponseWriter.WriteHeader(statusCode)
+}
+
+// Write records the body size, writing implies a 200 when no status was set
+func (w *statusRecorder) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.status = http.StatusOK
+	}
+	n, err := w.ResponseWriter.Write(b)
+	w.bytes += int64(n)
+	return n, err
+}
+
+// Status returns the status code sent, 200 when the handler wrote nothing
+func (w *statusRecorder) Status() int {
+	if w.status == 0 {
+		return http.StatusOK
+	}
+	return w.status
+}
+
+// Unwrap lets http.ResponseController reach the underlying writer
+func (w *statusRecorder) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
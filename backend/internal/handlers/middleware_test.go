@@ -0,0 +1,406 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/logging"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestOptionalAuthMiddleware(t *testing.T) {
+	token, err := testTokens.GenerateToken("user-123", "viewer@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		authorization  string
+		spoofedUserID  string
+		expectedUserID string
+	}{
+		{"anonymous", "", "", ""},
+		{"valid token", "Token " + token, "", "user-123"},
+		{"invalid token is ignored", "Token not-a-jwt", "", ""},
+		{"wrong scheme is ignored", "Bearer " + token, "", ""},
+		{"spoofed header is stripped", "", "admin", ""},
+		{"spoofed header is overridden", "Token " + token, "admin", "user-123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			if tt.spoofedUserID != "" {
+				req.Header.Set("X-User-ID", tt.spoofedUserID)
+			}
+
+			var gotUserID, gotHeader string
+			handler := StripIdentityHeadersMiddleware(OptionalAuthMiddleware(testTokens, func(w http.ResponseWriter, r *http.Request) {
+				gotUserID = currentUserID(r)
+				gotHeader = r.Header.Get("X-User-ID")
+				w.WriteHeader(http.StatusOK)
+			}))
+
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			if rr.Code != http.StatusOK {
+				t.Errorf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+			}
+			if gotUserID != tt.expectedUserID {
+				t.Errorf("Expected viewer %q, got %q", tt.expectedUserID, gotUserID)
+			}
+			if gotHeader != "" {
+				t.Errorf("Expected X-User-ID header to be stripped, got %q", gotHeader)
+			}
+		})
+	}
+}
+
+func TestAuthMiddleware_SetsCurrentUser(t *testing.T) {
+	token, err := testTokens.GenerateToken("user-123", "viewer@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+	req.Header.Set("Authorization", "Token "+token)
+
+	var gotUser auth.User
+	var gotOK bool
+	handler := AuthMiddleware(testTokens, func(w http.ResponseWriter, r *http.Request) {
+		gotUser, gotOK = auth.CurrentUser(r.Context())
+	})
+
+	handler(httptest.NewRecorder(), req)
+
+	if !gotOK || gotUser.ID != "user-123" || gotUser.Email != "viewer@example.com" {
+		t.Errorf("Expected current user user-123, got %+v (ok=%v)", gotUser, gotOK)
+	}
+}
+
+func TestProtectedHandler_IgnoresSpoofedHeader(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+
+	// Without the auth middleware a client-supplied identity header must not authenticate
+	req := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+	req.Header.Set("X-User-ID", "some-user")
+	rr := httptest.NewRecorder()
+	handler.GetCurrentUser(rr, req)
+
+	if rr.Code != http.StatusUnauthorized {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, rr.Code)
+	}
+}
+
+func TestWriteErrorResponse_RepositoryErrors(t *testing.T) {
+	tests := []struct {
+		name           string
+		err            error
+		expectedStatus int
+		expectedField  string
+		expectedMsg    string
+	}{
+		{"not found", &db.Error{Kind: db.ErrNotFound, Resource: "article"}, http.StatusNotFound, "article", "Article not found"},
+		{"wrapped not found", fmt.Errorf("failed: %w", &db.Error{Kind: db.ErrNotFound, Resource: "comment"}), http.StatusNotFound, "comment", "Comment not found"},
+		{"conflict", &db.Error{Kind: db.ErrConflict, Resource: "email"}, http.StatusUnprocessableEntity, "email", "Email already exists"},
+		{"forbidden", &db.Error{Kind: db.ErrForbidden, Resource: "comment"}, http.StatusForbidden, "permission", "You can only modify your own comments"},
+		{"bare sentinel", db.ErrNotFound, http.StatusNotFound, "resource", "Resource not found"},
+		{"other error", errors.New("disk I/O error"), http.StatusInternalServerError, "database", "Failed to fetch article"},
+		{"no error", nil, http.StatusInternalServerError, "database", "Failed to fetch article"},
+		{"timeout", fmt.Errorf("failed to count articles: %w", fmt.Errorf("%w: interrupted", db.ErrTimeout)), http.StatusServiceUnavailable, "database", "Database is busy, please retry"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := httptest.NewRecorder()
+			WriteErrorResponse(rr, http.StatusInternalServerError, "database", "Failed to fetch article", tt.err)
+
+			if rr.Code != tt.expectedStatus {
+				t.Errorf("Expected status %d, got %d", tt.expectedStatus, rr.Code)
+			}
+
+			var resp ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+
+			if msgs := resp.Errors[tt.expectedField]; len(msgs) != 1 || msgs[0] != tt.expectedMsg {
+				t.Errorf("Expected %s error %q, got %v", tt.expectedField, tt.expectedMsg, resp.Errors)
+			}
+
+			retryAfter := rr.Header().Get("Retry-After")
+			if (tt.expectedStatus == http.StatusServiceUnavailable) != (retryAfter != "") {
+				t.Errorf("Expected Retry-After only on 503, got %q", retryAfter)
+			}
+		})
+	}
+}
+
+func TestRequestLoggingMiddleware(t *testing.T) {
+	token, err := testTokens.GenerateToken("user-123", "viewer@example.com")
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	tests := []struct {
+		name           string
+		incomingID     string
+		authorization  string
+		expectReusedID bool
+		expectedUserID string
+	}{
+		{"generates an ID", "", "", false, ""},
+		{"reuses a valid incoming ID", "lb-trace-42", "Token " + token, true, "user-123"},
+		{"replaces an invalid incoming ID", "has spaces\nand newlines", "", false, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var logs bytes.Buffer
+			var handlerRequestID string
+			handler := RequestLoggingMiddleware(logging.New(&logs, slog.LevelInfo), OptionalAuthMiddleware(testTokens, func(w http.ResponseWriter, r *http.Request) {
+				handlerRequestID = logging.RequestID(r.Context())
+				logging.FromContext(r.Context()).Info("handling")
+				w.WriteHeader(http.StatusTeapot)
+			}))
+
+			req := httptest.NewRequest(http.MethodGet, "/api/articles", nil)
+			if tt.incomingID != "" {
+				req.Header.Set("X-Request-ID", tt.incomingID)
+			}
+			if tt.authorization != "" {
+				req.Header.Set("Authorization", tt.authorization)
+			}
+			rr := httptest.NewRecorder()
+			handler.ServeHTTP(rr, req)
+
+			requestID := rr.Header().Get("X-Request-ID")
+			if requestID == "" || requestID != handlerRequestID {
+				t.Fatalf("Expected the echoed ID %q to match the handler's %q", requestID, handlerRequestID)
+			}
+			if reused := requestID == tt.incomingID; reused != tt.expectReusedID {
+				t.Errorf("Expected reuse of incoming ID to be %v, got ID %q", tt.expectReusedID, requestID)
+			}
+
+			lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+			if len(lines) != 2 {
+				t.Fatalf("Expected a handler record and an access record, got:\n%s", logs.String())
+			}
+
+			var handlerRecord, accessRecord map[string]interface{}
+			if err := json.Unmarshal([]byte(lines[0]), &handlerRecord); err != nil {
+				t.Fatalf("Failed to decode handler record: %v", err)
+			}
+			if err := json.Unmarshal([]byte(lines[1]), &accessRecord); err != nil {
+				t.Fatalf("Failed to decode access record: %v", err)
+			}
+
+			if handlerRecord["request_id"] != requestID || accessRecord["request_id"] != requestID {
+				t.Errorf("Expected every record to carry request_id %q, got %v and %v", requestID, handlerRecord["request_id"], accessRecord["request_id"])
+			}
+			if accessRecord["status"] != float64(http.StatusTeapot) || accessRecord["method"] != http.MethodGet || accessRecord["path"] != "/api/articles" {
+				t.Errorf("Expected the access record to describe the request, got %v", accessRecord)
+			}
+			if accessRecord["user_id"] != tt.expectedUserID {
+				t.Errorf("Expected user_id %q, got %v", tt.expectedUserID, accessRecord["user_id"])
+			}
+			if _, ok := accessRecord["duration_ms"].(float64); !ok {
+				t.Errorf("Expected a duration_ms, got %v", accessRecord["duration_ms"])
+			}
+		})
+	}
+}
+
+func TestRecoveryMiddleware(t *testing.T) {
+	var logs bytes.Buffer
+	var reported error
+	var reportedStack []byte
+	reporter := func(r *http.Request, err error, stack []byte) {
+		reported, reportedStack = err, stack
+	}
+
+	handler := RequestLoggingMiddleware(logging.New(&logs, slog.LevelInfo), RecoveryMiddleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			var user *auth.User
+			_ = user.ID // nil pointer dereference
+		}), reporter))
+
+	req := httptest.NewRequest(http.MethodGet, "/api/user", nil)
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, req)
+
+	if rr.Code != http.StatusInternalServerError {
+		t.Fatalf("Expected status %d, got %d", http.StatusInternalServerError, rr.Code)
+	}
+	var body ErrorResponse
+	if err := json.Unmarshal(rr.Body.Bytes(), &body); err != nil || len(body.Errors["system"]) != 1 {
+		t.Errorf("Expected a RealWorld system error, got %s", rr.Body.String())
+	}
+
+	var runtimeErr runtime.Error
+	if !errors.As(reported, &runtimeErr) || len(reportedStack) == 0 {
+		t.Errorf("Expected the reporter to receive the runtime error and stack, got %v", reported)
+	}
+
+	requestID := rr.Header().Get("X-Request-ID")
+	lines := strings.Split(strings.TrimSpace(logs.String()), "\n")
+	if len(lines) != 2 {
+		t.Fatalf("Expected a panic record and an access record, got:\n%s", logs.String())
+	}
+
+	var panicRecord, accessRecord map[string]interface{}
+	if err := json.Unmarshal([]byte(lines[0]), &panicRecord); err != nil {
+		t.Fatalf("Failed to decode panic record: %v", err)
+	}
+	if err := json.Unmarshal([]byte(lines[1]), &accessRecord); err != nil {
+		t.Fatalf("Failed to decode access record: %v", err)
+	}
+
+	if panicRecord["request_id"] != requestID || !strings.Contains(panicRecord["stack"].(string), "TestRecoveryMiddleware") {
+		t.Errorf("Expected the panic record to carry the request ID and stack, got %v", panicRecord)
+	}
+	if accessRecord["status"] != float64(http.StatusInternalServerError) {
+		t.Errorf("Expected the access record to log a 500, got %v", accessRecord["status"])
+	}
+}
+
+func TestRecoveryMiddleware_ResponseStarted(t *testing.T) {
+	handler := RequestLoggingMiddleware(logging.New(io.Discard, slog.LevelInfo), RecoveryMiddleware(
+		http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			panic("after the header")
+		})))
+
+	rr := httptest.NewRecorder()
+	handler.ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+
+	if rr.Code != http.StatusOK || rr.Body.Len() != 0 {
+		t.Errorf("Expected the started response to be left alone, got %d %q", rr.Code, rr.Body.String())
+	}
+}
+
+func TestRecoveryMiddleware_AbortHandler(t *testing.T) {
+	handler := RecoveryMiddleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic(http.ErrAbortHandler)
+	}))
+
+	defer func() {
+		if rec := recover(); rec != http.ErrAbortHandler {
+			t.Errorf("Expected http.ErrAbortHandler to propagate, got %v", rec)
+		}
+	}()
+	handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/api/articles", nil))
+}
+
+func TestMetricsMiddleware(t *testing.T) {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/articles/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+	mux.HandleFunc("POST /api/articles/{slug}/comments", func(w http.ResponseWriter, r *http.Request) {
+		panic("handler bug")
+	})
+	handler := MetricsMiddleware(mux, RouteErrorMiddleware(mux))
+
+	tests := []struct {
+		name           string
+		method, path   string
+		labels         []string
+		expectedPanics bool
+	}{
+		{"labels by route pattern", http.MethodGet, "/api/articles/some-slug", []string{"GET", "/api/articles/{slug}", "404"}, false},
+		{"groups unknown routes", http.MethodGet, "/wp-login.php", []string{"GET", "unmatched", "404"}, false},
+		{"groups unknown methods", "PROPFIND", "/api/articles/some-slug", []string{"OTHER", "unmatched", "405"}, false},
+		{"counts panics as 500", http.MethodPost, "/api/articles/some-slug/comments", []string{"POST", "/api/articles/{slug}/comments", "500"}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			counter := metrics.HTTPRequests.WithLabelValues(tt.labels...)
+			before := testutil.ToFloat64(counter)
+
+			func() {
+				defer func() {
+					if rec := recover(); (rec != nil) != tt.expectedPanics {
+						t.Errorf("Expected panic %v, got %v", tt.expectedPanics, rec)
+					}
+				}()
+				handler.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, nil))
+			}()
+
+			if got := testutil.ToFloat64(counter); got != before+1 {
+				t.Errorf("Expected %v to be counted once, got %v after %v", tt.labels, got, before)
+			}
+		})
+	}
+}
+
+func TestTracingMiddleware(t *testing.T) {
+	exporter := tracetest.NewInMemoryExporter()
+	previous := otel.GetTracerProvider()
+	otel.SetTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSyncer(exporter)))
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer otel.SetTracerProvider(previous)
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /api/articles/{slug}", func(w http.ResponseWriter, r *http.Request) {
+		logging.FromContext(r.Context()).Info("handling")
+		w.WriteHeader(http.StatusInternalServerError)
+	})
+
+	var logs bytes.Buffer
+	handler := TracingMiddleware(mux, RequestLoggingMiddleware(logging.New(&logs, slog.LevelInfo), mux))
+
+	const traceID = "4bf92f3577b34da6a3ce929d0e0e4736"
+	req := httptest.NewRequest(http.MethodGet, "/api/articles/some-slug", nil)
+	req.Header.Set("traceparent", "00-"+traceID+"-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	spans := exporter.GetSpans()
+	if len(spans) != 1 {
+		t.Fatalf("Expected 1 span, got %d", len(spans))
+	}
+	span := spans[0]
+
+	if span.Name != "GET /api/articles/{slug}" {
+		t.Errorf("Expected the span to be named after the route, got %q", span.Name)
+	}
+	if span.SpanContext.TraceID().String() != traceID || span.Parent.SpanID().String() != "00f067aa0ba902b7" {
+		t.Errorf("Expected the span to continue the incoming trace, got trace %s parent %s", span.SpanContext.TraceID(), span.Parent.SpanID())
+	}
+	if span.Status.Code != codes.Error {
+		t.Errorf("Expected a 500 to mark the span as failed, got %v", span.Status)
+	}
+
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]interface{}
+		if err := json.Unmarshal([]byte(line), &record); err != nil {
+			t.Fatalf("Failed to decode log record: %v", err)
+		}
+		if record["trace_id"] != traceID {
+			t.Errorf("Expected every record to carry trace_id %s, got %v", traceID, record["trace_id"])
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+)
+
+// ProfileHandler handles profile-related HTTP requests
+type ProfileHandler struct {
+	userRepo   db.UserStore
+	followRepo db.FollowStore
+}
+
+// NewProfileHandler creates a new profile handler
+func NewProfileHandler(userRepo db.UserStore, followRepo db.FollowStore) *ProfileHandler {
+	return &ProfileHandler{
+		userRepo:   userRepo,
+		followRepo: followRepo,
+	}
+}
+
+// GetProfile handles GET /api/profiles/:username
+func (h *ProfileHandler) GetProfile(w http.ResponseWriter, r *http.Request) {
+	// Extract username from URL path
+	username := r.PathValue("username")
+	if username == "" {
+		WriteErrorResponse(w, http.StatusBadRequest, "username", "Invalid username")
+		return
+	}
+
+	// Get profile owner
+	user, err := h.userRepo.GetByUsername(r.Context(), username)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			WriteErrorResponse(w, http.StatusNotFound, "profile", "Profile not found")
+			return
+		}
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch profile", err)
+		return
+	}
+
+	// Authentication is optional for profiles, anonymous viewers never follow anyone
+	following, err := h.followRepo.IsFollowing(r.Context(), currentUserID(r), user.ID)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch follow status", err)
+		return
+	}
+
+	WriteJSONResponse(w, http.StatusOK, user.ToProfileResponse(following))
+}
+
+// FollowUser handles POST /api/profiles/:username/follow
+func (h *ProfileHandler) FollowUser(w http.ResponseWriter, r *http.Request) {
+	h.setFollowing(w, r, true)
+}
+
+// UnfollowUser handles DELETE /api/profiles/:username/follow
+func (h *ProfileHandler) UnfollowUser(w http.ResponseWriter, r *http.Request) {
+	h.setFollowing(w, r, false)
+}
+
+// setFollowing follows or unfollows the profile in the path on behalf of the authenticated user
+func (h *ProfileHandler) setFollowing(w http.ResponseWriter, r *http.Request, follow bool) {
+	// Get user from auth middleware
+	userID := currentUserID(r)
+	if userID == "" {
+		WriteErrorResponse(w, http.StatusUnauthorized, "auth", "Authentication required")
+		return
+	}
+
+	// Extract username from URL path
+	username := r.PathValue("username")
+	if username == "" {
+		WriteErrorResponse(w, http.StatusBadRequest, "username", "Invalid username")
+		return
+	}
+
+	// Get profile owner
+	user, err := h.userRepo.GetByUsername(r.Context(), username)
+	if err != nil {
+		if errors.Is(err, db.ErrNotFound) {
+			WriteErrorResponse(w, http.StatusNotFound, "profile", "Profile not found")
+			return
+		}
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch profile", err)
+		return
+	}
+
+	if user.ID == userID {
+		WriteErrorResponse(w, http.StatusUnprocessableEntity, "username", "You cannot follow yourself")
+		return
+	}
+
+	if follow {
+		err = h.followRepo.Follow(r.Context(), userID, user.ID)
+	} else {
+		err = h.followRepo.Unfollow(r.Context(), userID, user.ID)
+	}
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to update follow status", err)
+		return
+	}
+
+	WriteJSONResponse(w, http.StatusOK, user.ToProfileResponse(follow))
+}
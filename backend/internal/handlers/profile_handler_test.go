@@ -0,0 +1,123 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func setupTestProfileHandler(t *testing.T) (*ProfileHandler, *models.User, *models.User) {
+	_, store := setupTestHandler(t)
+
+	viewer := &models.User{Email: "viewer@example.com", Username: "viewer", PasswordHash: "hash"}
+	celeb := &models.User{Email: "celeb@example.com", Username: "celeb", PasswordHash: "hash", Bio: "famous"}
+	for _, user := range []*models.User{viewer, celeb} {
+		if err := store.Users().Create(context.Background(), user); err != nil {
+			t.Fatalf("Failed to create user: %v", err)
+		}
+	}
+
+	return NewProfileHandler(store.Users(), store.Follows()), viewer, celeb
+}
+
+func decodeProfile(t *testing.T, rr *httptest.ResponseRecorder) models.Profile {
+	var response models.ProfileResponse
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode profile response: %v", err)
+	}
+	return response.Profile
+}
+
+func TestProfileHandler_FollowFlow(t *testing.T) {
+	handler, viewer, _ := setupTestProfileHandler(t)
+
+	token, err := testTokens.GenerateToken(viewer.ID, viewer.Email)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	// Follow
+	req := httptest.NewRequest(http.MethodPost, "/api/profiles/celeb/follow", nil)
+	req.SetPathValue("username", "celeb")
+	req.Header.Set("Authorization", "Token "+token)
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.FollowUser)(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if profile := decodeProfile(t, rr); !profile.Following || profile.Bio != "famous" {
+		t.Errorf("Expected followed celeb profile, got %+v", profile)
+	}
+
+	// Authenticated GET reflects the follow
+	req = httptest.NewRequest(http.MethodGet, "/api/profiles/celeb", nil)
+	req.SetPathValue("username", "celeb")
+	req.Header.Set("Authorization", "Token "+token)
+	rr = httptest.NewRecorder()
+	OptionalAuthMiddleware(testTokens, handler.GetProfile)(rr, req)
+
+	if profile := decodeProfile(t, rr); !profile.Following {
+		t.Error("Expected following to be true for authenticated viewer")
+	}
+
+	// Anonymous GET never follows
+	req = httptest.NewRequest(http.MethodGet, "/api/profiles/celeb", nil)
+	req.SetPathValue("username", "celeb")
+	rr = httptest.NewRecorder()
+	OptionalAuthMiddleware(testTokens, handler.GetProfile)(rr, req)
+
+	if profile := decodeProfile(t, rr); profile.Following {
+		t.Error("Expected following to be false for anonymous viewer")
+	}
+
+	// Unfollow
+	req = httptest.NewRequest(http.MethodDelete, "/api/profiles/celeb/follow", nil)
+	req.SetPathValue("username", "celeb")
+	req.Header.Set("Authorization", "Token "+token)
+	rr = httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.UnfollowUser)(rr, req)
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if profile := decodeProfile(t, rr); profile.Following {
+		t.Error("Expected following to be false after unfollow")
+	}
+}
+
+func TestProfileHandler_GetProfile_NotFound(t *testing.T) {
+	handler, _, _ := setupTestProfileHandler(t)
+
+	req := httptest.NewRequest(http.MethodGet, "/api/profiles/nobody", nil)
+	req.SetPathValue("username", "nobody")
+	rr := httptest.NewRecorder()
+	OptionalAuthMiddleware(testTokens, handler.GetProfile)(rr, req)
+
+	if rr.Code != http.StatusNotFound {
+		t.Errorf("Expected status code %d, got %d", http.StatusNotFound, rr.Code)
+	}
+}
+
+func TestProfileHandler_FollowSelf(t *testing.T) {
+	handler, viewer, _ := setupTestProfileHandler(t)
+
+	token, err := testTokens.GenerateToken(viewer.ID, viewer.Email)
+	if err != nil {
+		t.Fatalf("Failed to generate token: %v", err)
+	}
+
+	req := httptest.NewRequest(http.MethodPost, "/api/profiles/viewer/follow", nil)
+	req.SetPathValue("username", "viewer")
+	req.Header.Set("Authorization", "Token "+token)
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.FollowUser)(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+}
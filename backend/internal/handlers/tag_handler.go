@@ -0,0 +1,72 @@
+package handlers
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/validation"
+)
+
+// TagHandler handles tag-related HTTP requests
+type TagHandler struct {
+	tagRepo db.TagStore
+}
+
+// NewTagHandler creates a new tag handler
+func NewTagHandler(tagRepo db.TagStore) *TagHandler {
+	return &TagHandler{
+		tagRepo: tagRepo,
+	}
+}
+
+// GetTags handles GET /api/tags
+//
+// Supported query parameters:
+//   - prefix: only return tags starting with the given text (autocomplete)
+//   - limit: maximum number of tags to return, a positive number
+//   - counts=true: include per-tag article counts in the response
+func (h *TagHandler) GetTags(w http.ResponseWriter, r *http.Request) {
+	// Parse query parameters
+	query := r.URL.Query()
+	filter := models.TagFilter{
+		Prefix: query.Get("prefix"),
+	}
+
+	if limitStr := query.Get("limit"); limitStr != "" {
+		limit, err := strconv.Atoi(limitStr)
+		if err != nil || limit < 1 {
+			v := validation.New()
+			v.Add("limit", "Limit must be a positive number")
+			WriteValidationErrors(w, v.Errors())
+			return
+		}
+		filter.Limit = limit
+	}
+
+	withCounts, _ := strconv.ParseBool(query.Get("counts"))
+
+	// Get tags
+	tagCounts, err := h.tagRepo.GetAll(r.Context(), filter)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch tags", err)
+		return
+	}
+
+	response := models.TagsResponse{
+		Tags: make([]string, 0, len(tagCounts)),
+	}
+	if withCounts {
+		response.Counts = make(map[string]int, len(tagCounts))
+	}
+
+	for _, tagCount := range tagCounts {
+		response.Tags = append(response.Tags, tagCount.Tag)
+		if withCounts {
+			response.Counts[tagCount.Tag] = tagCount.Count
+		}
+	}
+
+	WriteJSONResponse(w, http.StatusOK, response)
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+)
+
+func setupTestTagHandler(t *testing.T) *TagHandler {
+	_, store := setupTestHandler(t)
+
+	author := &models.User{Email: "author@example.com", Username: "author", PasswordHash: "hash"}
+	if err := store.Users().Create(context.Background(), author); err != nil {
+		t.Fatalf("Failed to create user: %v", err)
+	}
+
+	// go is used three times, golang twice and rust once
+	for _, tags := range [][]string{{"go", "golang"}, {"go", "golang", "rust"}, {"go"}} {
+		article := &models.Article{Title: "Tagged", Description: "Description", Body: "Body", TagList: tags}
+		article.Author.Username = author.Username
+		if err := store.Articles().Create(context.Background(), article); err != nil {
+			t.Fatalf("Failed to create article: %v", err)
+		}
+	}
+
+	return NewTagHandler(store.Tags())
+}
+
+func getTags(t *testing.T, handler *TagHandler, query string) (*httptest.ResponseRecorder, models.TagsResponse) {
+	req := httptest.NewRequest(http.MethodGet, "/api/tags"+query, nil)
+	rr := httptest.NewRecorder()
+	handler.GetTags(rr, req)
+
+	var response models.TagsResponse
+	if rr.Code == http.StatusOK {
+		if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+			t.Fatalf("Failed to decode tags response: %v", err)
+		}
+	}
+	return rr, response
+}
+
+func TestTagHandler_GetTags(t *testing.T) {
+	handler := setupTestTagHandler(t)
+
+	tests := []struct {
+		name     string
+		query    string
+		expected []string
+	}{
+		{"most popular first", "", []string{"go", "golang", "rust"}},
+		{"limit", "?limit=2", []string{"go", "golang"}},
+		{"prefix", "?prefix=go", []string{"go", "golang"}},
+		{"prefix matches case-insensitively", "?prefix=GOL", []string{"golang"}},
+		{"prefix and limit", "?prefix=go&limit=1", []string{"go"}},
+		{"unknown prefix", "?prefix=java", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr, response := getTags(t, handler, tt.query)
+
+			if rr.Code != http.StatusOK {
+				t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+			}
+			if !reflect.DeepEqual(response.Tags, tt.expected) {
+				t.Errorf("Expected tags %v, got %v", tt.expected, response.Tags)
+			}
+			if response.Counts != nil {
+				t.Errorf("Expected no counts unless requested, got %v", response.Counts)
+			}
+		})
+	}
+}
+
+func TestTagHandler_GetTags_Counts(t *testing.T) {
+	handler := setupTestTagHandler(t)
+
+	rr, response := getTags(t, handler, "?counts=true&prefix=go")
+
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	expected := map[string]int{"go": 3, "golang": 2}
+	if !reflect.DeepEqual(response.Counts, expected) {
+		t.Errorf("Expected counts %v, got %v", expected, response.Counts)
+	}
+}
+
+func TestTagHandler_GetTags_InvalidLimit(t *testing.T) {
+	handler := setupTestTagHandler(t)
+
+	for _, limit := range []string{"abc", "0", "-1"} {
+		t.Run(limit, func(t *testing.T) {
+			rr, _ := getTags(t, handler, "?limit="+limit)
+
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Fatalf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+
+			var response ErrorResponse
+			if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+				t.Fatalf("Failed to decode response: %v", err)
+			}
+			if len(response.Errors["limit"]) != 1 {
+				t.Errorf("Expected a limit error, got %v", response.Errors)
+			}
+		})
+	}
+}
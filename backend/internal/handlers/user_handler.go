@@ -2,82 +2,75 @@ package handlers
 
 import (
 	"encoding/json"
+	"errors"
 	"net/http"
-	"strings"
 
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/metrics"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/validation"
 )
 
 // UserHandler handles user-related HTTP requests
 type UserHandler struct {
-	userRepo *db.UserRepository
+	userRepo db.UserStore
+	tokens   *auth.TokenManager
 }
 
-// NewUserHandler creates a new user handler
-func NewUserHandler(userRepo *db.UserRepository) *UserHandler {
+// NewUserHandler creates a new user handler issuing tokens with the given token manager
+func NewUserHandler(userRepo db.UserStore, tokens *auth.TokenManager) *UserHandler {
 	return &UserHandler{
 		userRepo: userRepo,
+		tokens:   tokens,
 	}
 }
 
 // Register handles user registration
 func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	var req models.RegisterRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		WriteErrorResponse(w, http.StatusBadRequest, "body", "Invalid JSON")
 		return
 	}
 
-	// Validate required fields
-	if req.User.Email == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "email", "Email is required")
-		return
+	// Validate every field before reporting
+	v := validation.New()
+	if v.Required("email", req.User.Email) {
+		v.Email("email", req.User.Email)
 	}
-
-	if req.User.Username == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "username", "Username is required")
-		return
+	if v.Required("username", req.User.Username) {
+		v.Username("username", req.User.Username)
 	}
-
-	if req.User.Password == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "password", "Password is required")
-		return
+	if v.Required("password", req.User.Password) {
+		v.Password("password", req.User.Password)
 	}
 
-	// Validate email format (basic validation)
-	if !strings.Contains(req.User.Email, "@") {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "email", "Invalid email format")
-		return
+	// Check uniqueness of the fields that are otherwise valid
+	if !v.HasError("email") {
+		emailExists, err := h.userRepo.EmailExists(r.Context(), req.User.Email)
+		if err != nil {
+			WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error", err)
+			return
+		}
+		if emailExists {
+			v.Add("email", "Email already exists")
+		}
 	}
 
-	// Check if email already exists
-	emailExists, err := h.userRepo.EmailExists(req.User.Email)
-	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error")
-		return
-	}
-
-	if emailExists {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "email", "Email already exists")
-		return
-	}
-
-	// Check if username already exists
-	usernameExists, err := h.userRepo.UsernameExists(req.User.Username)
-	if err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error")
-		return
+	if !v.HasError("username") {
+		usernameExists, err := h.userRepo.UsernameExists(r.Context(), req.User.Username)
+		if err != nil {
+			WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error", err)
+			return
+		}
+		if usernameExists {
+			v.Add("username", "Username already exists")
+		}
 	}
 
-	if usernameExists {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "username", "Username already exists")
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
 		return
 	}
 
@@ -97,18 +90,20 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 		Image:        "",
 	}
 
-	if err := h.userRepo.Create(user); err != nil {
-		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create user")
+	if err := h.userRepo.Create(r.Context(), user); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to create user", err)
 		return
 	}
 
 	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email)
+	token, err := h.tokens.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		WriteErrorResponse(w, http.StatusInternalServerError, "token", "Failed to generate token")
 		return
 	}
 
+	metrics.UsersRegistered.Inc()
+
 	// Return user response
 	response := map[string]interface{}{
 		"user": user.ToResponse(token),
@@ -119,11 +114,6 @@ func (h *UserHandler) Register(w http.ResponseWriter, r *http.Request) {
 
 // Login handles user authentication
 func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodPost {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
-		return
-	}
-
 	var req models.LoginRequest
 	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
 		WriteErrorResponse(w, http.StatusBadRequest, "body", "Invalid JSON")
@@ -131,20 +121,22 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Validate required fields
-	if req.User.Email == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "email", "Email is required")
+	v := validation.New()
+	v.Required("email", req.User.Email)
+	v.Required("password", req.User.Password)
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
 		return
 	}
 
-	if req.User.Password == "" {
-		WriteErrorResponse(w, http.StatusUnprocessableEntity, "password", "Password is required")
+	// Get user by email
+	user, err := h.userRepo.GetByEmail(r.Context(), req.User.Email)
+	if errors.Is(err, db.ErrNotFound) {
+		WriteErrorResponse(w, http.StatusUnauthorized, "email", "Invalid email or password")
 		return
 	}
-
-	// Get user by email
-	user, err := h.userRepo.GetByEmail(req.User.Email)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusUnauthorized, "email", "Invalid email or password")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
@@ -155,7 +147,7 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 	}
 
 	// Generate JWT token
-	token, err := auth.GenerateToken(user.ID, user.Email)
+	token, err := h.tokens.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		WriteErrorResponse(w, http.StatusInternalServerError, "token", "Failed to generate token")
 		return
@@ -171,27 +163,138 @@ func (h *UserHandler) Login(w http.ResponseWriter, r *http.Request) {
 
 // GetCurrentUser returns the current authenticated user
 func (h *UserHandler) GetCurrentUser(w http.ResponseWriter, r *http.Request) {
-	if r.Method != http.MethodGet {
-		WriteErrorResponse(w, http.StatusMethodNotAllowed, "method", "Method not allowed")
+	// Get user ID from auth middleware
+	userID := currentUserID(r)
+	if userID == "" {
+		WriteErrorResponse(w, http.StatusUnauthorized, "token", "User not authenticated")
+		return
+	}
+
+	// Get user from database
+	user, err := h.userRepo.GetByID(r.Context(), userID)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
+		return
+	}
+
+	// Generate new token
+	token, err := h.tokens.GenerateToken(user.ID, user.Email)
+	if err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "token", "Failed to generate token")
 		return
 	}
 
-	// Get user ID from middleware
-	userID := r.Header.Get("X-User-ID")
+	// Return user response
+	response := map[string]interface{}{
+		"user": user.ToResponse(token),
+	}
+
+	WriteJSONResponse(w, http.StatusOK, response)
+}
+
+// UpdateCurrentUser handles PUT /api/user
+func (h *UserHandler) UpdateCurrentUser(w http.ResponseWriter, r *http.Request) {
+	// Get user ID from auth middleware
+	userID := currentUserID(r)
 	if userID == "" {
 		WriteErrorResponse(w, http.StatusUnauthorized, "token", "User not authenticated")
 		return
 	}
 
+	var req models.UserUpdateRequest
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		WriteErrorResponse(w, http.StatusBadRequest, "body", "Invalid JSON")
+		return
+	}
+
 	// Get user from database
-	user, err := h.userRepo.GetByID(userID)
+	user, err := h.userRepo.GetByID(r.Context(), userID)
 	if err != nil {
-		WriteErrorResponse(w, http.StatusNotFound, "user", "User not found")
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to fetch user", err)
 		return
 	}
 
-	// Generate new token
-	token, err := auth.GenerateToken(user.ID, user.Email)
+	// Re-validate every changed field before reporting, email and username
+	// are only checked when they differ from the stored values
+	v := validation.New()
+
+	emailChanged := req.User.Email != nil && *req.User.Email != user.Email
+	if emailChanged && v.Required("email", *req.User.Email) {
+		v.Email("email", *req.User.Email)
+	}
+
+	usernameChanged := req.User.Username != nil && *req.User.Username != user.Username
+	if usernameChanged && v.Required("username", *req.User.Username) {
+		v.Username("username", *req.User.Username)
+	}
+
+	// Password: an empty password is rejected rather than ignored
+	if req.User.Password != nil && v.Required("password", *req.User.Password) {
+		v.Password("password", *req.User.Password)
+	}
+
+	// Check uniqueness of the changed fields that are otherwise valid
+	if emailChanged && !v.HasError("email") {
+		emailExists, err := h.userRepo.EmailExists(r.Context(), *req.User.Email)
+		if err != nil {
+			WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error", err)
+			return
+		}
+		if emailExists {
+			v.Add("email", "Email already exists")
+		}
+	}
+
+	if usernameChanged && !v.HasError("username") {
+		usernameExists, err := h.userRepo.UsernameExists(r.Context(), *req.User.Username)
+		if err != nil {
+			WriteErrorResponse(w, http.StatusInternalServerError, "database", "Database error", err)
+			return
+		}
+		if usernameExists {
+			v.Add("username", "Username already exists")
+		}
+	}
+
+	if !v.Valid() {
+		WriteValidationErrors(w, v.Errors())
+		return
+	}
+
+	if emailChanged {
+		user.Email = *req.User.Email
+	}
+	if usernameChanged {
+		user.Username = *req.User.Username
+	}
+
+	// Password: hash the new password
+	if req.User.Password != nil {
+		hashedPassword, err := auth.HashPassword(*req.User.Password)
+		if err != nil {
+			WriteErrorResponse(w, http.StatusInternalServerError, "password", "Failed to process password")
+			return
+		}
+
+		user.PasswordHash = hashedPassword
+	}
+
+	// Bio and image may be cleared with an explicit empty string
+	if req.User.Bio != nil {
+		user.Bio = *req.User.Bio
+	}
+
+	if req.User.Image != nil {
+		user.Image = *req.User.Image
+	}
+
+	if err := h.userRepo.Update(r.Context(), user); err != nil {
+		WriteErrorResponse(w, http.StatusInternalServerError, "database", "Failed to update user", err)
+		return
+	}
+
+	// Issue a fresh token since the email claim may have changed
+	token, err := h.tokens.GenerateToken(user.ID, user.Email)
 	if err != nil {
 		WriteErrorResponse(w, http.StatusInternalServerError, "token", "Failed to generate token")
 		return
@@ -2,55 +2,28 @@ package handlers
 
 import (
 	"bytes"
-	"database/sql"
 	"encoding/json"
 	"net/http"
 	"net/http/httptest"
-	"os"
+	"strings"
 	"testing"
 
-	_ "github.com/mattn/go-sqlite3"
-	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/auth"
+	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/db/memory"
 	"github.com/vibe-coding-paradigm/realworld-build-from-prd/internal/models"
 )
 
-func setupTestHandler(t *testing.T) (*UserHandler, *sql.DB) {
-	// Set JWT secret for testing
-	os.Setenv("JWT_SECRET", "test-secret-key")
+// testTokens signs and validates the tokens of every handler test
+var testTokens = auth.NewTokenManager("test-secret-key")
 
-	// Create in-memory database for testing
-	database, err := sql.Open("sqlite3", ":memory:")
-	if err != nil {
-		t.Fatalf("Failed to create test database: %v", err)
-	}
-
-	// Create users table
-	createTableSQL := `
-	CREATE TABLE users (
-		id TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(16)))),
-		email TEXT NOT NULL UNIQUE,
-		username TEXT NOT NULL UNIQUE,
-		password_hash TEXT NOT NULL,
-		bio TEXT DEFAULT '',
-		image TEXT DEFAULT '',
-		created_at DATETIME DEFAULT CURRENT_TIMESTAMP,
-		updated_at DATETIME DEFAULT CURRENT_TIMESTAMP
-	);`
-
-	_, err = database.Exec(createTableSQL)
-	if err != nil {
-		t.Fatalf("Failed to create users table: %v", err)
-	}
-
-	userRepo := db.NewUserRepository(database)
-	handler := NewUserHandler(userRepo)
-
-	return handler, database
+// setupTestHandler returns a user handler backed by an in-memory store
+func setupTestHandler(t *testing.T) (*UserHandler, *memory.Store) {
+	store := memory.New()
+	return NewUserHandler(store.Users(), testTokens), store
 }
 
 func TestUserHandler_Register(t *testing.T) {
-	handler, db := setupTestHandler(t)
-	defer db.Close()
+	handler, _ := setupTestHandler(t)
 
 	// Valid registration request
 	registerReq := models.RegisterRequest{
@@ -100,8 +73,7 @@ func TestUserHandler_Register(t *testing.T) {
 }
 
 func TestUserHandler_Register_DuplicateEmail(t *testing.T) {
-	handler, db := setupTestHandler(t)
-	defer db.Close()
+	handler, _ := setupTestHandler(t)
 
 	// Create first user
 	registerReq := models.RegisterRequest{
@@ -144,9 +116,32 @@ func TestUserHandler_Register_DuplicateEmail(t *testing.T) {
 	}
 }
 
+func TestUserHandler_Register_ReportsEveryInvalidField(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+
+	body := `{"user":{"username":"no spaces allowed","email":"not-an-email","password":""}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/users", strings.NewReader(body))
+	rr := httptest.NewRecorder()
+	handler.Register(rr, req)
+
+	if rr.Code != http.StatusUnprocessableEntity {
+		t.Fatalf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+	}
+
+	var resp ErrorResponse
+	if err := json.NewDecoder(rr.Body).Decode(&resp); err != nil {
+		t.Fatalf("Failed to decode response: %v", err)
+	}
+
+	for _, field := range []string{"email", "username", "password"} {
+		if len(resp.Errors[field]) == 0 {
+			t.Errorf("Expected an error for %s, got %v", field, resp.Errors)
+		}
+	}
+}
+
 func TestUserHandler_Login(t *testing.T) {
-	handler, db := setupTestHandler(t)
-	defer db.Close()
+	handler, _ := setupTestHandler(t)
 
 	// First register a user
 	registerReq := models.RegisterRequest{
@@ -206,8 +201,7 @@ func TestUserHandler_Login(t *testing.T) {
 }
 
 func TestUserHandler_Login_InvalidCredentials(t *testing.T) {
-	handler, db := setupTestHandler(t)
-	defer db.Close()
+	handler, _ := setupTestHandler(t)
 
 	// Try to login with non-existent user
 	loginReq := models.LoginRequest{
@@ -229,3 +223,108 @@ func TestUserHandler_Login_InvalidCredentials(t *testing.T) {
 		t.Errorf("Expected status code %d, got %d", http.StatusUnauthorized, loginRecorder.Code)
 	}
 }
+
+func registerTestUser(t *testing.T, handler *UserHandler, username string) map[string]interface{} {
+	body := `{"user":{"username":"` + username + `","email":"` + username + `@example.com","password":"password123"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/users", bytes.NewReader([]byte(body)))
+	rr := httptest.NewRecorder()
+	handler.Register(rr, req)
+
+	if rr.Code != http.StatusCreated {
+		t.Fatalf("Failed to register %s: status %d", username, rr.Code)
+	}
+
+	var response map[string]map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode register response: %v", err)
+	}
+
+	return response["user"]
+}
+
+func updateTestUser(handler *UserHandler, token, body string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodPut, "/api/user", bytes.NewReader([]byte(body)))
+	req.Header.Set("Authorization", "Token "+token)
+	rr := httptest.NewRecorder()
+	AuthMiddleware(testTokens, handler.UpdateCurrentUser)(rr, req)
+	return rr
+}
+
+func TestUserHandler_UpdateCurrentUser(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+
+	user := registerTestUser(t, handler, "testuser")
+	token := user["token"].(string)
+
+	// Update bio, image, email and password at once
+	rr := updateTestUser(handler, token, `{"user":{"bio":"Hello","image":"https://example.com/me.png","email":"new@example.com","password":"newpassword"}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d: %s", http.StatusOK, rr.Code, rr.Body.String())
+	}
+
+	var response map[string]map[string]interface{}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode update response: %v", err)
+	}
+
+	updated := response["user"]
+	if updated["bio"] != "Hello" || updated["email"] != "new@example.com" || updated["username"] != "testuser" {
+		t.Errorf("Unexpected updated user: %v", updated)
+	}
+	if updated["token"] == nil || updated["token"] == "" {
+		t.Fatal("Expected refreshed token in response")
+	}
+
+	// Explicit empty strings clear bio and image
+	rr = updateTestUser(handler, updated["token"].(string), `{"user":{"bio":"","image":""}}`)
+	if rr.Code != http.StatusOK {
+		t.Fatalf("Expected status code %d, got %d", http.StatusOK, rr.Code)
+	}
+	if err := json.NewDecoder(rr.Body).Decode(&response); err != nil {
+		t.Fatalf("Failed to decode update response: %v", err)
+	}
+	if response["user"]["bio"] != "" || response["user"]["image"] != "" {
+		t.Errorf("Expected bio and image to be cleared, got %v", response["user"])
+	}
+	if response["user"]["email"] != "new@example.com" {
+		t.Errorf("Expected omitted email to stay unchanged, got %v", response["user"]["email"])
+	}
+
+	// The new password is used for login
+	loginBody := `{"user":{"email":"new@example.com","password":"newpassword"}}`
+	req := httptest.NewRequest(http.MethodPost, "/api/users/login", bytes.NewReader([]byte(loginBody)))
+	loginRecorder := httptest.NewRecorder()
+	handler.Login(loginRecorder, req)
+
+	if loginRecorder.Code != http.StatusOK {
+		t.Errorf("Expected login with new password to succeed, got %d", loginRecorder.Code)
+	}
+}
+
+func TestUserHandler_UpdateCurrentUser_Conflicts(t *testing.T) {
+	handler, _ := setupTestHandler(t)
+
+	registerTestUser(t, handler, "taken")
+	user := registerTestUser(t, handler, "testuser")
+	token := user["token"].(string)
+
+	tests := []struct {
+		name string
+		body string
+	}{
+		{"duplicate username", `{"user":{"username":"taken"}}`},
+		{"duplicate email", `{"user":{"email":"taken@example.com"}}`},
+		{"invalid email", `{"user":{"email":"not-an-email"}}`},
+		{"empty username", `{"user":{"username":""}}`},
+		{"empty password", `{"user":{"password":""}}`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			rr := updateTestUser(handler, token, tt.body)
+			if rr.Code != http.StatusUnprocessableEntity {
+				t.Errorf("Expected status code %d, got %d", http.StatusUnprocessableEntity, rr.Code)
+			}
+		})
+	}
+}
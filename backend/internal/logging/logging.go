@@ -0,0 +1,103 @@
+// Package logging provides the JSON logger of the server and carries the
+// request-scoped logger, request ID and user through the request context.
+package logging
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"log/slog"
+	"strings"
+)
+
+// New returns a logger writing one JSON object per line to w
+func New(w io.Writer, level slog.Level) *slog.Logger {
+	return slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: level}))
+}
+
+// ParseLevel parses a level name such as "debug", "info", "warn" or "error"
+func ParseLevel(name string) (slog.Level, error) {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(name))); err != nil {
+		return 0, fmt.Errorf("unknown log level %q", name)
+	}
+	return level, nil
+}
+
+// contextKey is unexported so no other package can read or overwrite the values
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	requestKey
+)
+
+// request holds what is known about the current request. It is shared by
+// pointer so that inner middleware can fill in the user for the access log
+// written by outer middleware.
+type request struct {
+	id     string
+	userID string
+}
+
+// NewContext returns a copy of ctx carrying a request with the given ID and a
+// logger that adds the ID to every record
+func NewContext(ctx context.Context, logger *slog.Logger, requestID string) context.Context {
+	ctx = context.WithValue(ctx, requestKey, &request{id: requestID})
+	return context.WithValue(ctx, loggerKey, logger.With("request_id", requestID))
+}
+
+// FromContext returns the request logger of ctx, or the default logger outside a request
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// RequestID returns the ID of the request of ctx, or an empty string outside a request
+func RequestID(ctx context.Context) string {
+	if req, ok := ctx.Value(requestKey).(*request); ok {
+		return req.id
+	}
+	return ""
+}
+
+// SetUser records the authenticated user of the request of ctx for its access log
+func SetUser(ctx context.Context, userID string) {
+	if req, ok := ctx.Value(requestKey).(*request); ok {
+		req.userID = userID
+	}
+}
+
+// User returns the user recorded by SetUser, or an empty string for anonymous requests
+func User(ctx context.Context) string {
+	if req, ok := ctx.Value(requestKey).(*request); ok {
+		return req.userID
+	}
+	return ""
+}
+
+// NewRequestID returns a random 128-bit request ID in hex
+func NewRequestID() string {
+	b := make([]byte, 16)
+	// crypto/rand.Read does not fail on supported platforms
+	_, _ = rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ValidRequestID reports whether an incoming X-Request-ID can be reused. IDs are
+// echoed in headers and logs, so only short printable ASCII values are accepted.
+func ValidRequestID(id string) bool {
+	if id == "" || len(id) > 128 {
+		return false
+	}
+	for i := 0; i < len(id); i++ {
+		if id[i] < 0x21 || id[i] > 0x7e {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,64 @@
+package logging
+
+import (
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+)
+
+func TestParseLevel(t *testing.T) {
+	tests := map[string]slog.Level{
+		"debug": slog.LevelDebug,
+		"INFO":  slog.LevelInfo,
+		"warn":  slog.LevelWarn,
+		"error": slog.LevelError,
+	}
+
+	for name, expected := range tests {
+		if level, err := ParseLevel(name); err != nil || level != expected {
+			t.Errorf("Expected %q to parse as %v, got %v, %v", name, expected, level, err)
+		}
+	}
+
+	if _, err := ParseLevel("loud"); err == nil {
+		t.Error("Expected an unknown level to fail")
+	}
+}
+
+func TestValidRequestID(t *testing.T) {
+	tests := map[string]bool{
+		"":                        false,
+		"abc-123":                 true,
+		"Root=1-5759e988-bd862e3": true,
+		"with space":              false,
+		"new\nline":               false,
+		strings.Repeat("a", 129):  false,
+	}
+
+	for id, expected := range tests {
+		if got := ValidRequestID(id); got != expected {
+			t.Errorf("ValidRequestID(%q) = %v, expected %v", id, got, expected)
+		}
+	}
+
+	if id := NewRequestID(); !ValidRequestID(id) || id == NewRequestID() {
+		t.Errorf("Expected generated IDs to be valid and unique, got %q", id)
+	}
+}
+
+func TestContext(t *testing.T) {
+	ctx := context.Background()
+	if FromContext(ctx) != slog.Default() || RequestID(ctx) != "" || User(ctx) != "" {
+		t.Fatal("Expected the defaults outside a request")
+	}
+	// Setting the user outside a request is a no-op
+	SetUser(ctx, "user-1")
+
+	ctx = NewContext(ctx, slog.Default(), "req-1")
+	SetUser(ctx, "user-1")
+
+	if RequestID(ctx) != "req-1" || User(ctx) != "user-1" {
+		t.Errorf("Expected request req-1 by user-1, got %q by %q", RequestID(ctx), User(ctx))
+	}
+}
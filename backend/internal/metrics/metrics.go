@@ -0,0 +1,89 @@
+// Package metrics defines the Prometheus metrics of the server and serves them
+// in the text exposition format on /metrics.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// namespace prefixes every metric name
+const namespace = "conduit"
+
+// Registry holds the server metrics. A dedicated registry keeps metrics
+// registered by dependencies on the default registry out of /metrics.
+var Registry = prometheus.NewRegistry()
+
+var (
+	// HTTPRequests counts served requests by method, route pattern and status code
+	HTTPRequests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "http_requests_total",
+		Help:      "HTTP requests served, by method, route and status code.",
+	}, []string{"method", "route", "status"})
+
+	// HTTPRequestDuration observes the latency of served requests by method and route pattern
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: namespace,
+		Name:      "http_request_duration_seconds",
+		Help:      "Latency of HTTP requests, by method and route.",
+		Buckets:   prometheus.DefBuckets,
+	}, []string{"method", "route"})
+
+	// UsersRegistered counts successful registrations
+	UsersRegistered = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "users_registered_total",
+		Help:      "Users registered.",
+	})
+
+	// ArticlesCreated counts successfully created articles
+	ArticlesCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "articles_created_total",
+		Help:      "Articles created.",
+	})
+
+	// CommentsPosted counts successfully posted comments
+	CommentsPosted = prometheus.NewCounter(prometheus.CounterOpts{
+		Namespace: namespace,
+		Name:      "comments_posted_total",
+		Help:      "Comments posted.",
+	})
+)
+
+func init() {
+	Registry.MustRegister(
+		HTTPRequests,
+		HTTPRequestDuration,
+		UsersRegistered,
+		ArticlesCreated,
+		CommentsPosted,
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+	)
+}
+
+// RegisterDB exposes the connection pool statistics of db (sql.DBStats) as
+// go_sql_* gauges and counters labelled with db_name
+func RegisterDB(db *sql.DB, name string) error {
+	return Registry.Register(collectors.NewDBStatsCollector(db, name))
+}
+
+// ObserveRequest records a served request. route is the matched pattern such as
+// /api/articles/{slug}, never the raw path, so the number of series stays bounded.
+func ObserveRequest(method, route string, status int, duration time.Duration) {
+	HTTPRequests.WithLabelValues(method, route, strconv.Itoa(status)).Inc()
+	HTTPRequestDuration.WithLabelValues(method, route).Observe(duration.Seconds())
+}
+
+// Handler serves the registered metrics
+func Handler() http.Handler {
+	return promhttp.HandlerFor(Registry, promhttp.HandlerOpts{Registry: Registry})
+}
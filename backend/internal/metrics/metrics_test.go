@@ -0,0 +1,54 @@
+package metrics
+
+import (
+	"database/sql"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+func TestObserveRequest(t *testing.T) {
+	before := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/api/articles/{slug}", "404"))
+
+	ObserveRequest("GET", "/api/articles/{slug}", http.StatusNotFound, 20*time.Millisecond)
+
+	if got := testutil.ToFloat64(HTTPRequests.WithLabelValues("GET", "/api/articles/{slug}", "404")); got != before+1 {
+		t.Errorf("Expected the request count to grow by 1, got %v after %v", got, before)
+	}
+}
+
+func TestHandler(t *testing.T) {
+	database, err := sql.Open("sqlite3", ":memory:")
+	if err != nil {
+		t.Fatalf("Failed to open database: %v", err)
+	}
+	defer database.Close()
+
+	if err := RegisterDB(database, "metrics_test"); err != nil {
+		t.Fatalf("Failed to register database metrics: %v", err)
+	}
+	UsersRegistered.Inc()
+	ObserveRequest("POST", "/api/users", http.StatusCreated, time.Millisecond)
+
+	rr := httptest.NewRecorder()
+	Handler().ServeHTTP(rr, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	body, _ := io.ReadAll(rr.Body)
+
+	for _, want := range []string{
+		`conduit_http_requests_total{method="POST",route="/api/users",status="201"}`,
+		`conduit_http_request_duration_seconds_bucket{method="POST",route="/api/users",le="0.005"}`,
+		`conduit_users_registered_total`,
+		`go_sql_open_connections{db_name="metrics_test"}`,
+		`go_goroutines`,
+	} {
+		if !strings.Contains(string(body), want) {
+			t.Errorf("Expected /metrics to contain %s", want)
+		}
+	}
+}
@@ -63,8 +63,10 @@ type ArticleFilter struct {
 	Tag       string
 	Author    string
 	Favorited string
-	Limit     int
-	Offset    int
+	// FollowedBy restricts results to authors followed by the given user ID
+	FollowedBy string
+	Limit      int
+	Offset     int
 }
 
 // ToResponse converts Article to the response format with author info
@@ -85,7 +87,7 @@ func (a *Article) ToResponse(author *User) ArticleResponse {
 				Username:  author.Username,
 				Bio:       author.Bio,
 				Image:     author.Image,
-				Following: false, // TODO: Implement following logic
+				Following: a.Author.Following,
 			},
 		},
 	}
@@ -42,7 +42,7 @@ func (c *Comment) ToResponse(author *User) CommentResponse {
 				Username:  author.Username,
 				Bio:       author.Bio,
 				Image:     author.Image,
-				Following: false, // TODO: Implement following logic
+				Following: c.Author.Following,
 			},
 		},
 	}
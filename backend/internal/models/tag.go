@@ -0,0 +1,21 @@
+package models
+
+// TagCount represents a tag together with the number of articles using it
+type TagCount struct {
+	Tag   string `json:"tag"`
+	Count int    `json:"count"`
+}
+
+// TagsResponse represents the API response format for tags
+type TagsResponse struct {
+	Tags []string `json:"tags"`
+	// Counts is only included when usage counts are requested
+	Counts map[string]int `json:"counts,omitempty"`
+}
+
+// TagFilter represents filtering options for tags
+type TagFilter struct {
+	// Prefix restricts results to tags starting with the given text (autocomplete)
+	Prefix string
+	Limit  int
+}
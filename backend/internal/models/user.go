@@ -25,6 +25,19 @@ type UserResponse struct {
 	Image    string `json:"image"`
 }
 
+// Profile represents the public profile of a user as seen by a viewer
+type Profile struct {
+	Username  string `json:"username"`
+	Bio       string `json:"bio"`
+	Image     string `json:"image"`
+	Following bool   `json:"following"`
+}
+
+// ProfileResponse represents the API response format for profiles
+type ProfileResponse struct {
+	Profile Profile `json:"profile"`
+}
+
 // RegisterRequest represents the request payload for user registration
 type RegisterRequest struct {
 	User struct {
@@ -42,14 +55,16 @@ type LoginRequest struct {
 	} `json:"user"`
 }
 
-// UserUpdateRequest represents the request payload for updating user profile
+// UserUpdateRequest represents the request payload for updating user profile.
+// Fields are pointers so that omitted fields are left unchanged while an explicit
+// empty string (e.g. "bio": "") clears the value.
 type UserUpdateRequest struct {
 	User struct {
-		Email    string `json:"email,omitempty"`
-		Username string `json:"username,omitempty"`
-		Password string `json:"password,omitempty"`
-		Bio      string `json:"bio,omitempty"`
-		Image    string `json:"image,omitempty"`
+		Email    *string `json:"email,omitempty"`
+		Username *string `json:"username,omitempty"`
+		Password *string `json:"password,omitempty"`
+		Bio      *string `json:"bio,omitempty"`
+		Image    *string `json:"image,omitempty"`
 	} `json:"user"`
 }
 
@@ -63,3 +78,15 @@ func (u *User) ToResponse(token string) UserResponse {
 		Image:    u.Image,
 	}
 }
+
+// ToProfileResponse converts a User to the profile response format as seen by a viewer
+func (u *User) ToProfileResponse(following bool) ProfileResponse {
+	return ProfileResponse{
+		Profile: Profile{
+			Username:  u.Username,
+			Bio:       u.Bio,
+			Image:     u.Image,
+			Following: following,
+		},
+	}
+}
@@ -0,0 +1,83 @@
+// Package tracing sets up OpenTelemetry for the server. Spans are created
+// through the global tracer provider, which does nothing until Setup installs an
+// exporter, so instrumented packages cost next to nothing when tracing is off.
+package tracing
+
+import (
+	"context"
+	"fmt"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+)
+
+// Exporter names where finished spans are sent
+type Exporter string
+
+const (
+	// ExporterNone drops every span, the default
+	ExporterNone Exporter = "none"
+	// ExporterOTLP sends spans to an OpenTelemetry collector over OTLP/HTTP
+	ExporterOTLP Exporter = "otlp"
+)
+
+// ParseExporter parses an exporter name
+func ParseExporter(name string) (Exporter, error) {
+	switch Exporter(name) {
+	case ExporterNone, ExporterOTLP:
+		return Exporter(name), nil
+	default:
+		return "", fmt.Errorf("unknown traces exporter %q, expected none or otlp", name)
+	}
+}
+
+// Options configures Setup
+type Options struct {
+	Exporter Exporter
+	// Endpoint is the OTLP/HTTP URL of the collector, e.g. http://localhost:4318.
+	// When empty the exporter falls back to OTEL_EXPORTER_OTLP_* or its default.
+	Endpoint string
+	// ServiceName identifies the server in the tracing backend
+	ServiceName string
+}
+
+// Setup installs the W3C trace context propagator, so incoming traceparent
+// headers are continued even when spans are not exported, and the tracer
+// provider for the configured exporter. The returned function flushes pending
+// spans and must be called before the process exits.
+func Setup(ctx context.Context, opts Options) (shutdown func(context.Context) error, err error) {
+	otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator(propagation.TraceContext{}, propagation.Baggage{}))
+
+	if opts.Exporter != ExporterOTLP {
+		return func(context.Context) error { return nil }, nil
+	}
+
+	var exporterOpts []otlptracehttp.Option
+	if opts.Endpoint != "" {
+		exporterOpts = append(exporterOpts, otlptracehttp.WithEndpointURL(opts.Endpoint))
+	}
+	exporter, err := otlptracehttp.New(ctx, exporterOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create OTLP exporter: %w", err)
+	}
+
+	// resource.Default adds the SDK attributes and OTEL_RESOURCE_ATTRIBUTES
+	res, err := resource.Merge(resource.Default(), resource.NewWithAttributes(semconv.SchemaURL,
+		semconv.ServiceName(opts.ServiceName),
+	))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create tracing resource: %w", err)
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(res),
+	)
+	otel.SetTracerProvider(provider)
+
+	return provider.Shutdown, nil
+}
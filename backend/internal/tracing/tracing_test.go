@@ -0,0 +1,52 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestParseExporter(t *testing.T) {
+	for _, name := range []string{"none", "otlp"} {
+		if _, err := ParseExporter(name); err != nil {
+			t.Errorf("Expected %q to be valid, got %v", name, err)
+		}
+	}
+
+	if _, err := ParseExporter("jaeger"); err == nil {
+		t.Error("Expected an unknown exporter to fail")
+	}
+}
+
+func TestSetup_None(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterNone, ServiceName: "test"})
+	if err != nil {
+		t.Fatalf("Failed to set up tracing: %v", err)
+	}
+	defer shutdown(context.Background())
+
+	// Without an exporter an incoming trace is still continued
+	header := http.Header{}
+	header.Set("traceparent", "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	ctx := otel.GetTextMapPropagator().Extract(context.Background(), propagation.HeaderCarrier(header))
+
+	if got := trace.SpanContextFromContext(ctx).TraceID().String(); got != "4bf92f3577b34da6a3ce929d0e0e4736" {
+		t.Errorf("Expected the incoming trace ID, got %q", got)
+	}
+}
+
+func TestSetup_OTLP(t *testing.T) {
+	shutdown, err := Setup(context.Background(), Options{Exporter: ExporterOTLP, Endpoint: "http://localhost:4318", ServiceName: "test"})
+	if err != nil {
+		t.Fatalf("Failed to set up tracing: %v", err)
+	}
+
+	// Nothing has been exported yet, so shutting down does not contact the collector
+	if err := shutdown(context.Background()); err != nil {
+		t.Errorf("Failed to shut down: %v", err)
+	}
+}
@@ -2,60 +2,174 @@ package utils
 
 import (
 	"fmt"
-	"regexp"
 	"strings"
+	"unicode"
 )
 
-// GenerateSlug creates a URL-friendly slug from a title
-func GenerateSlug(title string) string {
-	if title == "" {
-		return "untitled"
-	}
+// SlugStrategy controls how non-ASCII characters are handled when generating slugs
+type SlugStrategy string
 
-	// Convert to lowercase
-	slug := strings.ToLower(title)
+const (
+	// SlugStrategyASCII keeps only [a-z0-9] and drops every other character
+	SlugStrategyASCII SlugStrategy = "ascii"
+	// SlugStrategyUnicode keeps Unicode letters and digits, which browsers percent-encode in URLs
+	SlugStrategyUnicode SlugStrategy = "unicode"
+	// SlugStrategyTransliterate converts Hangul, Cyrillic and accented Latin letters to ASCII
+	SlugStrategyTransliterate SlugStrategy = "transliterate"
+)
 
-	// Replace spaces with hyphens
-	slug = strings.ReplaceAll(slug, " ", "-")
+// SlugOptions configures slug generation
+type SlugOptions struct {
+	Strategy SlugStrategy
+	// MaxLength is the maximum number of characters of a base slug, 0 means unlimited.
+	// Slugs are truncated at the last word boundary that fits.
+	MaxLength int
+	// Reserved slugs are never returned by GenerateUniqueSlug because they would
+	// shadow a route, e.g. "feed" in /api/articles/feed
+	Reserved []string
+}
 
-	// Remove special characters, keep only alphanumeric and hyphens
-	reg := regexp.MustCompile(`[^a-z0-9\-]`)
-	slug = reg.ReplaceAllString(slug, "")
+// DefaultSlugOptions returns the options used unless SetSlugOptions is called
+func DefaultSlugOptions() SlugOptions {
+	return SlugOptions{
+		Strategy:  SlugStrategyTransliterate,
+		MaxLength: 100,
+		Reserved:  []string{"feed"},
+	}
+}
 
-	// Remove multiple consecutive hyphens
-	reg = regexp.MustCompile(`-+`)
-	slug = reg.ReplaceAllString(slug, "-")
+var slugOptions = DefaultSlugOptions()
 
-	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
+// SetSlugOptions replaces the options used by GenerateSlug and GenerateUniqueSlug.
+// It is meant to be called once at startup.
+func SetSlugOptions(opts SlugOptions) {
+	slugOptions = opts
+}
 
-	// Ensure slug is not empty
-	if slug == "" {
-		slug = "untitled"
+// ParseSlugStrategy parses a strategy name such as "unicode"
+func ParseSlugStrategy(name string) (SlugStrategy, error) {
+	switch strategy := SlugStrategy(strings.ToLower(strings.TrimSpace(name))); strategy {
+	case SlugStrategyASCII, SlugStrategyUnicode, SlugStrategyTransliterate:
+		return strategy, nil
+	default:
+		return "", fmt.Errorf("unknown slug strategy %q", name)
 	}
+}
 
-	return slug
+// GenerateSlug creates a URL-friendly slug from a title
+func GenerateSlug(title string) string {
+	return generateSlug(title, slugOptions)
 }
 
-// GenerateUniqueSlug creates a unique slug by appending a number suffix if needed
+// GenerateUniqueSlug creates a unique slug by appending a number suffix if needed.
+// Reserved slugs are treated as taken. The base slug is shortened to make room for
+// the suffix, so the result never exceeds the maximum length.
 func GenerateUniqueSlug(title string, existingSlugs []string) string {
-	baseSlug := GenerateSlug(title)
+	opts := slugOptions
+	baseSlug := generateSlug(title, opts)
+	taken := append(append([]string{}, existingSlugs...), opts.Reserved...)
 
 	// Check if base slug is unique
-	if !contains(existingSlugs, baseSlug) {
+	if !contains(taken, baseSlug) {
 		return baseSlug
 	}
 
 	// Append number suffix until unique
 	for i := 1; i <= 1000; i++ { // Reasonable limit to prevent infinite loop
-		candidateSlug := fmt.Sprintf("%s-%d", baseSlug, i)
-		if !contains(existingSlugs, candidateSlug) {
+		candidateSlug := appendSuffix(baseSlug, i, opts.MaxLength)
+		if !contains(taken, candidateSlug) {
 			return candidateSlug
 		}
 	}
 
 	// Fallback with timestamp if all numbers are taken
-	return fmt.Sprintf("%s-%d", baseSlug, 999999)
+	return appendSuffix(baseSlug, 999999, opts.MaxLength)
+}
+
+// appendSuffix appends "-n" to a slug, first truncating the slug at a word
+// boundary so that the result fits in maxLength characters
+func appendSuffix(slug string, n, maxLength int) string {
+	suffix := fmt.Sprintf("-%d", n)
+	if maxLength > 0 {
+		// Keep at least one character when the suffix alone nearly fills the limit
+		slug = truncateSlug(slug, max(maxLength-len(suffix), 1))
+	}
+	return slug + suffix
+}
+
+// generateSlug creates a slug from a title using the given options
+func generateSlug(title string, opts SlugOptions) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	writeHyphen := func() {
+		if b.Len() > 0 {
+			pendingHyphen = true
+		}
+	}
+	write := func(s string) {
+		if s == "" {
+			return
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteString(s)
+	}
+
+	for _, r := range strings.ToLower(title) {
+		switch {
+		case unicode.IsSpace(r) || r == '-':
+			// Spaces and hyphens separate words, consecutive separators collapse
+			writeHyphen()
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			write(string(r))
+		case r < unicode.MaxASCII:
+			// ASCII punctuation is dropped
+		default:
+			write(convertRune(r, opts.Strategy))
+		}
+	}
+
+	slug := truncateSlug(b.String(), opts.MaxLength)
+
+	// Ensure slug is not empty
+	if slug == "" {
+		slug = "untitled"
+	}
+
+	return slug
+}
+
+// convertRune converts a non-ASCII rune according to the strategy
+func convertRune(r rune, strategy SlugStrategy) string {
+	switch strategy {
+	case SlugStrategyUnicode:
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) {
+			return string(r)
+		}
+	case SlugStrategyTransliterate:
+		return transliterate(r)
+	}
+	return ""
+}
+
+// truncateSlug shortens a slug to maxLength characters, cutting at the last hyphen when possible
+func truncateSlug(slug string, maxLength int) string {
+	runes := []rune(slug)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return slug
+	}
+
+	truncated := string(runes[:maxLength])
+	if runes[maxLength] != '-' {
+		if i := strings.LastIndex(truncated, "-"); i > 0 {
+			truncated = truncated[:i]
+		}
+	}
+
+	return strings.Trim(truncated, "-")
 }
 
 // contains checks if a slice contains a specific string
@@ -44,3 +44,110 @@ func TestGenerateUniqueSlug(t *testing.T) {
 		t.Errorf("GenerateUniqueSlug('Hello World', existing) = %q, expected %q", result, expected)
 	}
 }
+
+func TestGenerateSlug_Strategies(t *testing.T) {
+	tests := []struct {
+		strategy SlugStrategy
+		input    string
+		expected string
+	}{
+		{SlugStrategyTransliterate, "안녕하세요 세계", "annyeonghaseyo-segye"},
+		{SlugStrategyTransliterate, "Go 언어로 만든 API", "go-eoneoro-mandeun-api"},
+		{SlugStrategyTransliterate, "Привет мир", "privet-mir"},
+		{SlugStrategyTransliterate, "Crème Brûlée", "creme-brulee"},
+		{SlugStrategyUnicode, "안녕하세요 세계!", "안녕하세요-세계"},
+		{SlugStrategyUnicode, "Crème Brûlée", "crème-brûlée"},
+		{SlugStrategyASCII, "안녕하세요 세계", "untitled"},
+		{SlugStrategyASCII, "Crème Brûlée", "crme-brle"},
+	}
+
+	for _, test := range tests {
+		opts := DefaultSlugOptions()
+		opts.Strategy = test.strategy
+		result := generateSlug(test.input, opts)
+		if result != test.expected {
+			t.Errorf("generateSlug(%q, %s) = %q, expected %q", test.input, test.strategy, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateSlug_MaxLength(t *testing.T) {
+	tests := []struct {
+		input     string
+		maxLength int
+		expected  string
+	}{
+		{"Hello Wonderful World", 15, "hello-wonderful"},
+		{"Hello Wonderful World", 14, "hello"},
+		{"Supercalifragilistic", 10, "supercalif"},
+		{"Hello World", 0, "hello-world"},
+		{"안녕 세계", 3, "안녕"},
+	}
+
+	for _, test := range tests {
+		opts := DefaultSlugOptions()
+		opts.Strategy = SlugStrategyUnicode
+		opts.MaxLength = test.maxLength
+		result := generateSlug(test.input, opts)
+		if result != test.expected {
+			t.Errorf("generateSlug(%q) with max length %d = %q, expected %q", test.input, test.maxLength, result, test.expected)
+		}
+	}
+}
+
+func TestGenerateUniqueSlug_MaxLength(t *testing.T) {
+	opts := DefaultSlugOptions()
+	opts.MaxLength = 15
+	SetSlugOptions(opts)
+	t.Cleanup(func() { SetSlugOptions(DefaultSlugOptions()) })
+
+	tests := []struct {
+		existing []string
+		expected string
+	}{
+		{nil, "hello-wonderful"},
+		// The suffix replaces the last word rather than exceeding the limit
+		{[]string{"hello-wonderful"}, "hello-1"},
+		{[]string{"hello-wonderful", "hello-1"}, "hello-2"},
+	}
+
+	for _, test := range tests {
+		result := GenerateUniqueSlug("Hello Wonderful World", test.existing)
+		if result != test.expected {
+			t.Errorf("GenerateUniqueSlug with existing %v = %q, expected %q", test.existing, result, test.expected)
+		}
+		if len(result) > opts.MaxLength {
+			t.Errorf("GenerateUniqueSlug returned %q, longer than %d characters", result, opts.MaxLength)
+		}
+	}
+
+	// A single word is cut mid-word
+	opts.MaxLength = 10
+	SetSlugOptions(opts)
+	if result := GenerateUniqueSlug("Supercalifragilistic", []string{"supercalif"}); result != "supercal-1" {
+		t.Errorf("GenerateUniqueSlug('Supercalifragilistic') = %q, expected %q", result, "supercal-1")
+	}
+}
+
+func TestGenerateUniqueSlug_Reserved(t *testing.T) {
+	result := GenerateUniqueSlug("Feed", nil)
+	if result != "feed-1" {
+		t.Errorf("GenerateUniqueSlug('Feed', nil) = %q, expected %q", result, "feed-1")
+	}
+
+	result = GenerateUniqueSlug("Feed", []string{"feed-1"})
+	if result != "feed-2" {
+		t.Errorf("GenerateUniqueSlug('Feed', [feed-1]) = %q, expected %q", result, "feed-2")
+	}
+}
+
+func TestParseSlugStrategy(t *testing.T) {
+	strategy, err := ParseSlugStrategy(" Unicode ")
+	if err != nil || strategy != SlugStrategyUnicode {
+		t.Errorf("ParseSlugStrategy(' Unicode ') = %q, %v", strategy, err)
+	}
+
+	if _, err := ParseSlugStrategy("emoji"); err == nil {
+		t.Error("ParseSlugStrategy('emoji') should fail")
+	}
+}
@@ -0,0 +1,60 @@
+package utils
+
+// Hangul syllables are composed algorithmically from initial, medial and final jamo:
+// syllable = 0xAC00 + (initial*21 + medial)*28 + final
+const (
+	hangulBase   = 0xAC00
+	hangulLast   = 0xD7A3
+	hangulMedial = 21
+	hangulFinal  = 28
+)
+
+// Revised Romanization of Korean, applied syllable by syllable
+var (
+	hangulInitials = []string{
+		"g", "kk", "n", "d", "tt", "r", "m", "b", "pp", "s",
+		"ss", "", "j", "jj", "ch", "k", "t", "p", "h",
+	}
+	hangulMedials = []string{
+		"a", "ae", "ya", "yae", "eo", "e", "yeo", "ye", "o", "wa", "wae",
+		"oe", "yo", "u", "wo", "we", "wi", "yu", "eu", "ui", "i",
+	}
+	hangulFinals = []string{
+		"", "k", "k", "k", "n", "n", "n", "t", "l", "k", "m", "l", "l", "l",
+		"p", "l", "m", "p", "p", "t", "t", "ng", "t", "t", "k", "t", "p", "t",
+	}
+)
+
+// transliterations maps lowercase accented Latin and Cyrillic letters to ASCII
+var transliterations = map[rune]string{
+	// Latin
+	'à': "a", 'á': "a", 'â': "a", 'ã': "a", 'ä': "a", 'å': "a", 'ā': "a", 'ă': "a", 'ą': "a",
+	'æ': "ae", 'ç': "c", 'ć': "c", 'č': "c", 'ď': "d", 'đ': "d",
+	'è': "e", 'é': "e", 'ê': "e", 'ë': "e", 'ē': "e", 'ę': "e", 'ě': "e",
+	'ğ': "g", 'ì': "i", 'í': "i", 'î': "i", 'ï': "i", 'ī': "i", 'ı': "i",
+	'ł': "l", 'ñ': "n", 'ń': "n", 'ň': "n",
+	'ò': "o", 'ó': "o", 'ô': "o", 'õ': "o", 'ö': "o", 'ø': "o", 'ō': "o", 'ő': "o", 'œ': "oe",
+	'ř': "r", 'ß': "ss", 'ś': "s", 'š': "s", 'ş': "s", 'ť': "t", 'þ': "th",
+	'ù': "u", 'ú': "u", 'û': "u", 'ü': "u", 'ū': "u", 'ů': "u", 'ű': "u",
+	'ý': "y", 'ÿ': "y", 'ź': "z", 'ż': "z", 'ž': "z",
+	// Cyrillic
+	'а': "a", 'б': "b", 'в': "v", 'г': "g", 'д': "d", 'е': "e", 'ё': "yo", 'ж': "zh",
+	'з': "z", 'и': "i", 'й': "y", 'к': "k", 'л': "l", 'м': "m", 'н': "n", 'о': "o",
+	'п': "p", 'р': "r", 'с': "s", 'т': "t", 'у': "u", 'ф': "f", 'х': "kh", 'ц': "ts",
+	'ч': "ch", 'ш': "sh", 'щ': "shch", 'ъ': "", 'ы': "y", 'ь': "", 'э': "e", 'ю': "yu",
+	'я': "ya", 'і': "i", 'ї': "yi", 'є': "ye", 'ґ': "g",
+}
+
+// transliterate converts a lowercase rune to ASCII, returning an empty string
+// for characters without a known transliteration
+func transliterate(r rune) string {
+	if r >= hangulBase && r <= hangulLast {
+		index := int(r - hangulBase)
+		initial := index / (hangulMedial * hangulFinal)
+		medial := (index % (hangulMedial * hangulFinal)) / hangulFinal
+		final := index % hangulFinal
+		return hangulInitials[initial] + hangulMedials[medial] + hangulFinals[final]
+	}
+
+	return transliterations[r]
+}
@@ -0,0 +1,121 @@
+package validation
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+)
+
+// Length limits for user input
+const (
+	UsernameMinLength = 3
+	UsernameMaxLength = 30
+	PasswordMinLength = 6
+	// PasswordMaxLength is the longest password bcrypt can hash, in bytes
+	PasswordMaxLength    = 72
+	TitleMaxLength       = 255
+	DescriptionMaxLength = 1000
+	BodyMaxLength        = 100000
+	TagMaxLength         = 50
+	CommentMaxLength     = 10000
+)
+
+var (
+	emailPattern    = regexp.MustCompile(`^[a-zA-Z0-9._%+-]+@[a-zA-Z0-9.-]+\.[a-zA-Z]{2,}$`)
+	usernamePattern = regexp.MustCompile(`^[a-zA-Z0-9_]+$`)
+)
+
+// Errors maps a field to its validation messages, matching the RealWorld error body
+type Errors map[string][]string
+
+// Validator collects the violations of every field so they can be reported together
+type Validator struct {
+	errors Errors
+}
+
+// New creates a validator without violations
+func New() *Validator {
+	return &Validator{errors: Errors{}}
+}
+
+// Add records a violation for a field
+func (v *Validator) Add(field, message string) {
+	v.errors[field] = append(v.errors[field], message)
+}
+
+// HasError reports whether a field has a violation
+func (v *Validator) HasError(field string) bool {
+	return len(v.errors[field]) > 0
+}
+
+// Valid reports whether no violations were recorded
+func (v *Validator) Valid() bool {
+	return len(v.errors) == 0
+}
+
+// Errors returns the recorded violations
+func (v *Validator) Errors() Errors {
+	return v.errors
+}
+
+// Required records a violation when the value is blank and reports whether it is present
+func (v *Validator) Required(field, value string) bool {
+	if strings.TrimSpace(value) == "" {
+		v.Add(field, fieldName(field)+" is required")
+		return false
+	}
+	return true
+}
+
+// MaxLength records a violation when the value is longer than max characters
+func (v *Validator) MaxLength(field, value string, max int) {
+	if utf8.RuneCountInString(value) > max {
+		v.Add(field, fmt.Sprintf("%s must be at most %d characters", fieldName(field), max))
+	}
+}
+
+// Email records a violation when a non-blank value is not an email address
+func (v *Validator) Email(field, value string) {
+	if value != "" && !emailPattern.MatchString(value) {
+		v.Add(field, "Invalid email format")
+	}
+}
+
+// Username records a violation when a non-blank value is not 3-30 letters, digits or underscores
+func (v *Validator) Username(field, value string) {
+	if value == "" {
+		return
+	}
+	if len(value) < UsernameMinLength || len(value) > UsernameMaxLength || !usernamePattern.MatchString(value) {
+		v.Add(field, fmt.Sprintf("%s must be %d-%d characters, alphanumeric and underscores only",
+			fieldName(field), UsernameMinLength, UsernameMaxLength))
+	}
+}
+
+// Password records a violation when a non-blank value is too short or too long to hash
+func (v *Validator) Password(field, value string) {
+	if value == "" {
+		return
+	}
+	if len(value) < PasswordMinLength || len(value) > PasswordMaxLength {
+		v.Add(field, fmt.Sprintf("%s must be %d-%d characters", fieldName(field), PasswordMinLength, PasswordMaxLength))
+	}
+}
+
+// Tags records a violation for every overlong tag. Blank tags are dropped when saving.
+func (v *Validator) Tags(field string, tags []string) {
+	for _, tag := range tags {
+		if tag = strings.TrimSpace(tag); utf8.RuneCountInString(tag) > TagMaxLength {
+			v.Add(field, fmt.Sprintf("Tag %q must be at most %d characters", tag, TagMaxLength))
+		}
+	}
+}
+
+// fieldName capitalizes a field for use in a message, e.g. "email" becomes "Email"
+func fieldName(field string) string {
+	if field == "" {
+		return field
+	}
+	return strings.ToUpper(field[:1]) + field[1:]
+}
@@ -0,0 +1,64 @@
+package validation
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestValidator_CollectsEveryField(t *testing.T) {
+	v := New()
+	v.Required("email", "")
+	if v.Required("username", "a!") {
+		v.Username("username", "a!")
+	}
+	if v.Required("password", "123") {
+		v.Password("password", "123")
+	}
+
+	if v.Valid() {
+		t.Fatal("Expected validation errors")
+	}
+
+	errs := v.Errors()
+	expected := map[string]string{
+		"email":    "Email is required",
+		"username": "Username must be 3-30 characters, alphanumeric and underscores only",
+		"password": "Password must be 6-72 characters",
+	}
+	for field, message := range expected {
+		if len(errs[field]) != 1 || errs[field][0] != message {
+			t.Errorf("Expected %s error %q, got %v", field, message, errs[field])
+		}
+	}
+}
+
+func TestValidator_Rules(t *testing.T) {
+	tests := []struct {
+		name  string
+		check func(v *Validator)
+		valid bool
+	}{
+		{"blank is missing", func(v *Validator) { v.Required("title", "   ") }, false},
+		{"present", func(v *Validator) { v.Required("title", "Title") }, true},
+		{"valid email", func(v *Validator) { v.Email("email", "user@example.com") }, true},
+		{"invalid email", func(v *Validator) { v.Email("email", "user@example") }, false},
+		{"valid username", func(v *Validator) { v.Username("username", "user_01") }, true},
+		{"username with hyphen", func(v *Validator) { v.Username("username", "user-01") }, false},
+		{"username too long", func(v *Validator) { v.Username("username", strings.Repeat("a", 31)) }, false},
+		{"password too long", func(v *Validator) { v.Password("password", strings.Repeat("a", 73)) }, false},
+		{"max length counts characters", func(v *Validator) { v.MaxLength("title", strings.Repeat("가", 255), TitleMaxLength) }, true},
+		{"over max length", func(v *Validator) { v.MaxLength("title", strings.Repeat("a", 256), TitleMaxLength) }, false},
+		{"tags", func(v *Validator) { v.Tags("tagList", []string{"go", " ", strings.Repeat("t", 50)}) }, true},
+		{"overlong tag", func(v *Validator) { v.Tags("tagList", []string{strings.Repeat("t", 51)}) }, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v := New()
+			tt.check(v)
+			if v.Valid() != tt.valid {
+				t.Errorf("Expected valid %v, got errors %v", tt.valid, v.Errors())
+			}
+		})
+	}
+}
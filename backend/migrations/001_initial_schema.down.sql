@@ -0,0 +1,9 @@
+-- Reverts 001_initial_schema.up.sql, dependent tables first
+
+DROP TABLE favorites;
+DROP TABLE follows;
+DROP TABLE article_tags;
+DROP TABLE tags;
+DROP TABLE comments;
+DROP TABLE articles;
+DROP TABLE users;
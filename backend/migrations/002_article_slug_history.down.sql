@@ -0,0 +1,3 @@
+-- Reverts 002_article_slug_history.up.sql
+
+DROP TABLE article_slug_history;
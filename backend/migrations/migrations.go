@@ -0,0 +1,11 @@
+// Package migrations embeds the schema migrations so binaries do not need the
+// migrations directory on disk. SQLite migrations live at the root and the
+// PostgreSQL ones in the postgres directory.
+package migrations
+
+import "embed"
+
+// FS holds every migration file
+//
+//go:embed *.sql postgres/*.sql
+var FS embed.FS
@@ -0,0 +1,85 @@
+-- Initial database schema for RealWorld Conduit application (PostgreSQL)
+-- Mirrors ../001_initial_schema.up.sql with IDs that are 32 character hex strings like
+-- SQLite's. Indexes already created by UNIQUE and PRIMARY KEY constraints are left out.
+
+-- Users table
+CREATE TABLE users (
+    id TEXT PRIMARY KEY DEFAULT md5(random()::text || clock_timestamp()::text),
+    email TEXT NOT NULL UNIQUE,
+    username TEXT NOT NULL UNIQUE,
+    password_hash TEXT NOT NULL,
+    bio TEXT DEFAULT '',
+    image TEXT DEFAULT '',
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Articles table
+CREATE TABLE articles (
+    id TEXT PRIMARY KEY DEFAULT md5(random()::text || clock_timestamp()::text),
+    slug TEXT NOT NULL UNIQUE,
+    title TEXT NOT NULL,
+    description TEXT NOT NULL,
+    body TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Comments table
+CREATE TABLE comments (
+    id TEXT PRIMARY KEY DEFAULT md5(random()::text || clock_timestamp()::text),
+    body TEXT NOT NULL,
+    author_id TEXT NOT NULL,
+    article_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (author_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+-- Tags table
+CREATE TABLE tags (
+    id TEXT PRIMARY KEY DEFAULT md5(random()::text || clock_timestamp()::text),
+    name TEXT NOT NULL UNIQUE,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP
+);
+
+-- Article-Tag many-to-many relationship
+CREATE TABLE article_tags (
+    article_id TEXT NOT NULL,
+    tag_id TEXT NOT NULL,
+    PRIMARY KEY (article_id, tag_id),
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE,
+    FOREIGN KEY (tag_id) REFERENCES tags(id) ON DELETE CASCADE
+);
+
+-- User follows relationship
+CREATE TABLE follows (
+    follower_id TEXT NOT NULL,
+    following_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (follower_id, following_id),
+    FOREIGN KEY (follower_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (following_id) REFERENCES users(id) ON DELETE CASCADE
+);
+
+-- Article favorites relationship
+CREATE TABLE favorites (
+    user_id TEXT NOT NULL,
+    article_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    PRIMARY KEY (user_id, article_id),
+    FOREIGN KEY (user_id) REFERENCES users(id) ON DELETE CASCADE,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+-- Performance optimization indexes
+CREATE INDEX idx_articles_author_id ON articles(author_id);
+CREATE INDEX idx_articles_created_at ON articles(created_at DESC);
+CREATE INDEX idx_comments_article_id ON comments(article_id);
+CREATE INDEX idx_comments_author_id ON comments(author_id);
+CREATE INDEX idx_follows_following_id ON follows(following_id);
+CREATE INDEX idx_favorites_article_id ON favorites(article_id);
+CREATE INDEX idx_article_tags_tag_id ON article_tags(tag_id);
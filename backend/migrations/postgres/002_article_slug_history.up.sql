@@ -0,0 +1,11 @@
+-- Previous slugs of articles, kept so links to a renamed article keep resolving
+
+-- Article slug history table
+CREATE TABLE article_slug_history (
+    slug TEXT PRIMARY KEY,
+    article_id TEXT NOT NULL,
+    created_at TIMESTAMPTZ DEFAULT CURRENT_TIMESTAMP,
+    FOREIGN KEY (article_id) REFERENCES articles(id) ON DELETE CASCADE
+);
+
+CREATE INDEX idx_article_slug_history_article_id ON article_slug_history(article_id);
@@ -36,6 +36,9 @@ func init() {
 	if jwtSecret == "" {
 		log.Fatal("JWT_SECRET environment variable is required")
 	}
+	if err := utils.LoadSlugOptionsFromEnv(); err != nil {
+		log.Fatalf("Invalid slug configuration: %v", err)
+	}
 
 	// Initialize repository
 	repo = repository.NewDynamoDBRepository(dynamoClient, tableName)
@@ -43,7 +46,7 @@ func init() {
 
 // CreateArticleHandler handles POST /articles requests
 func CreateArticleHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Create article function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -69,14 +72,15 @@ func CreateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 
 	claims, err := auth.ValidateToken(token, jwtSecret)
 	if err != nil {
-		log.Printf("Token validation failed: %v", err)
+		logger.Warn("Token validation failed", "error", err)
 		return utils.ErrorResponse(401, "authorization", "Invalid or expired token")
 	}
+	utils.SetUser(ctx, claims.UserID)
 
 	// Parse request body
 	var createReq models.CreateArticleRequest
 	if err := json.Unmarshal([]byte(request.Body), &createReq); err != nil {
-		log.Printf("Failed to parse JSON: %v", err)
+		logger.Info("Failed to parse JSON", "error", err)
 		return utils.ErrorResponse(400, "body", "Invalid JSON format")
 	}
 
@@ -102,7 +106,7 @@ func CreateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 	// Create article in repository
 	err = repo.Create(article, claims.UserID, claims.Username, "", "") // TODO: Get user bio and image
 	if err != nil {
-		log.Printf("Failed to create article: %v", err)
+		logger.Error("Failed to create article", "error", err)
 		return utils.ErrorResponse(500, "server", "Failed to create article")
 	}
 
@@ -119,5 +123,5 @@ func CreateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 }
 
 func main() {
-	lambda.Start(CreateArticleHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("create_article", utils.WithRecovery(CreateArticleHandler))))
 }
\ No newline at end of file
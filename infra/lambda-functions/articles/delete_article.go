@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 
@@ -41,7 +42,7 @@ func init() {
 
 // DeleteArticleHandler handles DELETE /articles/:slug requests
 func DeleteArticleHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Delete article function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -67,9 +68,10 @@ func DeleteArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 
 	claims, err := auth.ValidateToken(token, jwtSecret)
 	if err != nil {
-		log.Printf("Token validation failed: %v", err)
+		logger.Warn("Token validation failed", "error", err)
 		return utils.ErrorResponse(401, "authorization", "Invalid or expired token")
 	}
+	utils.SetUser(ctx, claims.UserID)
 
 	// Extract slug from path parameters
 	slug := request.PathParameters["slug"]
@@ -80,11 +82,11 @@ func DeleteArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 	// Delete article from repository
 	err = repo.Delete(slug, claims.UserID)
 	if err != nil {
-		log.Printf("Failed to delete article: %v", err)
-		if err.Error() == "article not found" {
+		logger.Error("Failed to delete article", "error", err)
+		if errors.Is(err, repository.ErrNotFound) {
 			return utils.ErrorResponse(404, "article", "Article not found")
 		}
-		if err.Error() == "unauthorized: user does not own this article" {
+		if errors.Is(err, repository.ErrForbidden) {
 			return utils.ErrorResponse(403, "authorization", "You can only delete your own articles")
 		}
 		return utils.ErrorResponse(500, "server", "Failed to delete article")
@@ -95,5 +97,5 @@ func DeleteArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 }
 
 func main() {
-	lambda.Start(DeleteArticleHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("delete_article", utils.WithRecovery(DeleteArticleHandler))))
 }
\ No newline at end of file
@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 
@@ -42,7 +43,7 @@ func init() {
 
 // FavoriteArticleHandler handles POST/DELETE /articles/:slug/favorite requests
 func FavoriteArticleHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Favorite article function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -68,9 +69,10 @@ func FavoriteArticleHandler(ctx context.Context, request events.APIGatewayProxyR
 
 	claims, err := auth.ValidateToken(token, jwtSecret)
 	if err != nil {
-		log.Printf("Token validation failed: %v", err)
+		logger.Warn("Token validation failed", "error", err)
 		return utils.ErrorResponse(401, "authorization", "Invalid or expired token")
 	}
+	utils.SetUser(ctx, claims.UserID)
 
 	// Extract slug from path parameters
 	slug := request.PathParameters["slug"]
@@ -85,8 +87,8 @@ func FavoriteArticleHandler(ctx context.Context, request events.APIGatewayProxyR
 		// Favorite the article
 		article, err = repo.FavoriteArticle(slug, claims.UserID)
 		if err != nil {
-			log.Printf("Failed to favorite article: %v", err)
-			if err.Error() == "article not found" {
+			logger.Error("Failed to favorite article", "error", err)
+			if errors.Is(err, repository.ErrNotFound) {
 				return utils.ErrorResponse(404, "article", "Article not found")
 			}
 			return utils.ErrorResponse(500, "server", "Failed to favorite article")
@@ -95,8 +97,8 @@ func FavoriteArticleHandler(ctx context.Context, request events.APIGatewayProxyR
 		// Unfavorite the article
 		article, err = repo.UnfavoriteArticle(slug, claims.UserID)
 		if err != nil {
-			log.Printf("Failed to unfavorite article: %v", err)
-			if err.Error() == "article not found" {
+			logger.Error("Failed to unfavorite article", "error", err)
+			if errors.Is(err, repository.ErrNotFound) {
 				return utils.ErrorResponse(404, "article", "Article not found")
 			}
 			return utils.ErrorResponse(500, "server", "Failed to unfavorite article")
@@ -112,5 +114,5 @@ func FavoriteArticleHandler(ctx context.Context, request events.APIGatewayProxyR
 }
 
 func main() {
-	lambda.Start(FavoriteArticleHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("favorite_article", utils.WithRecovery(FavoriteArticleHandler))))
 }
\ No newline at end of file
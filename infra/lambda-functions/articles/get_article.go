@@ -2,6 +2,7 @@ package main
 
 import (
 	"context"
+	"errors"
 	"log"
 	"os"
 
@@ -42,7 +43,7 @@ func init() {
 
 // GetArticleHandler handles GET /articles/:slug requests
 func GetArticleHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Get article function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -68,6 +69,7 @@ func GetArticleHandler(ctx context.Context, request events.APIGatewayProxyReques
 			claims, err := auth.ValidateToken(token, jwtSecret)
 			if err == nil {
 				userID = claims.UserID
+				utils.SetUser(ctx, userID)
 			}
 			// Don't fail if token is invalid - this is a public endpoint
 		}
@@ -76,8 +78,11 @@ func GetArticleHandler(ctx context.Context, request events.APIGatewayProxyReques
 	// Get article from repository
 	article, err := repo.GetBySlug(slug, userID)
 	if err != nil {
-		log.Printf("Failed to get article by slug %s: %v", slug, err)
-		return utils.ErrorResponse(404, "article", "Article not found")
+		logger.Error("Failed to get article", "slug", slug, "error", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.ErrorResponse(404, "article", "Article not found")
+		}
+		return utils.ErrorResponse(500, "server", "Failed to get article")
 	}
 
 	// Prepare response
@@ -89,5 +94,5 @@ func GetArticleHandler(ctx context.Context, request events.APIGatewayProxyReques
 }
 
 func main() {
-	lambda.Start(GetArticleHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("get_article", utils.WithRecovery(GetArticleHandler))))
 }
\ No newline at end of file
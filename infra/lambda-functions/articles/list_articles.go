@@ -43,7 +43,7 @@ func init() {
 
 // ListArticlesHandler handles GET /articles requests
 func ListArticlesHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("List articles function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -63,6 +63,7 @@ func ListArticlesHandler(ctx context.Context, request events.APIGatewayProxyRequ
 			claims, err := auth.ValidateToken(token, jwtSecret)
 			if err == nil {
 				userID = claims.UserID
+				utils.SetUser(ctx, userID)
 			}
 			// Don't fail if token is invalid - this is a public endpoint
 		}
@@ -94,7 +95,7 @@ func ListArticlesHandler(ctx context.Context, request events.APIGatewayProxyRequ
 	// Get articles from repository
 	articles, totalCount, err := repo.GetAll(filter, userID)
 	if err != nil {
-		log.Printf("Failed to get articles: %v", err)
+		logger.Error("Failed to get articles", "error", err)
 		return utils.ErrorResponse(500, "server", "Failed to retrieve articles")
 	}
 
@@ -108,5 +109,5 @@ func ListArticlesHandler(ctx context.Context, request events.APIGatewayProxyRequ
 }
 
 func main() {
-	lambda.Start(ListArticlesHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("list_articles", utils.WithRecovery(ListArticlesHandler))))
 }
\ No newline at end of file
@@ -3,11 +3,11 @@ package repository
 import (
 	"fmt"
 	"strconv"
-	"strings"
 	"time"
     "sort"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
 	"github.com/google/uuid"
@@ -92,7 +92,7 @@ func (r *DynamoDBRepository) GetBySlug(slug string, userID string) (*models.Arti
 	}
 	
 	if result.Item == nil {
-		return nil, fmt.Errorf("article not found")
+		return nil, &Error{Kind: ErrNotFound, Resource: "article"}
 	}
 	
 	var article models.Article
@@ -220,7 +220,7 @@ func (r *DynamoDBRepository) Update(slug string, updateReq *models.UpdateArticle
 	
 	// Check ownership
 	if article.AuthorID != userID {
-		return nil, fmt.Errorf("unauthorized: user does not own this article")
+		return nil, &Error{Kind: ErrForbidden, Resource: "article"}
 	}
 	
 	// Prepare update expression
@@ -309,7 +309,7 @@ func (r *DynamoDBRepository) Delete(slug string, userID string) error {
 	
 	// Check ownership
 	if article.AuthorID != userID {
-		return fmt.Errorf("unauthorized: user does not own this article")
+		return &Error{Kind: ErrForbidden, Resource: "article"}
 	}
 	
 	// Delete the article
@@ -359,7 +359,7 @@ func (r *DynamoDBRepository) FavoriteArticle(slug string, userID string) (*model
 	
 	if err != nil {
 		// If it's a conditional check failed, it means already favorited
-		if strings.Contains(err.Error(), "ConditionalCheckFailedException") {
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
 			return article, nil // Already favorited, return current state
 		}
 		return nil, fmt.Errorf("failed to favorite article: %w", err)
@@ -0,0 +1,26 @@
+package repository
+
+import "errors"
+
+// Sentinel errors returned by the repository, compare them with errors.Is
+var (
+	ErrNotFound  = errors.New("not found")
+	ErrForbidden = errors.New("forbidden")
+)
+
+// Error is a repository error about a specific resource such as an "article".
+// errors.Is matches it against its Kind.
+type Error struct {
+	Kind     error
+	Resource string
+}
+
+// Error returns a message such as "article not found"
+func (e *Error) Error() string {
+	return e.Resource + " " + e.Kind.Error()
+}
+
+// Unwrap returns the sentinel error
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
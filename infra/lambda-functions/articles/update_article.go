@@ -3,6 +3,7 @@ package main
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"log"
 	"os"
 
@@ -36,6 +37,9 @@ func init() {
 	if jwtSecret == "" {
 		log.Fatal("JWT_SECRET environment variable is required")
 	}
+	if err := utils.LoadSlugOptionsFromEnv(); err != nil {
+		log.Fatalf("Invalid slug configuration: %v", err)
+	}
 
 	// Initialize repository
 	repo = repository.NewDynamoDBRepository(dynamoClient, tableName)
@@ -43,7 +47,7 @@ func init() {
 
 // UpdateArticleHandler handles PUT /articles/:slug requests
 func UpdateArticleHandler(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Update article function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight
 	if request.HTTPMethod == "OPTIONS" {
@@ -69,9 +73,10 @@ func UpdateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 
 	claims, err := auth.ValidateToken(token, jwtSecret)
 	if err != nil {
-		log.Printf("Token validation failed: %v", err)
+		logger.Warn("Token validation failed", "error", err)
 		return utils.ErrorResponse(401, "authorization", "Invalid or expired token")
 	}
+	utils.SetUser(ctx, claims.UserID)
 
 	// Extract slug from path parameters
 	slug := request.PathParameters["slug"]
@@ -82,18 +87,18 @@ func UpdateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 	// Parse request body
 	var updateReq models.UpdateArticleRequest
 	if err := json.Unmarshal([]byte(request.Body), &updateReq); err != nil {
-		log.Printf("Failed to parse JSON: %v", err)
+		logger.Info("Failed to parse JSON", "error", err)
 		return utils.ErrorResponse(400, "body", "Invalid JSON format")
 	}
 
 	// Update article in repository
 	updatedArticle, err := repo.Update(slug, &updateReq, claims.UserID)
 	if err != nil {
-		log.Printf("Failed to update article: %v", err)
-		if err.Error() == "article not found" {
+		logger.Error("Failed to update article", "error", err)
+		if errors.Is(err, repository.ErrNotFound) {
 			return utils.ErrorResponse(404, "article", "Article not found")
 		}
-		if err.Error() == "unauthorized: user does not own this article" {
+		if errors.Is(err, repository.ErrForbidden) {
 			return utils.ErrorResponse(403, "authorization", "You can only update your own articles")
 		}
 		return utils.ErrorResponse(500, "server", "Failed to update article")
@@ -108,5 +113,5 @@ func UpdateArticleHandler(ctx context.Context, request events.APIGatewayProxyReq
 }
 
 func main() {
-	lambda.Start(UpdateArticleHandler)
+	lambda.Start(utils.WithTracing(utils.WithLogging("update_article", utils.WithRecovery(UpdateArticleHandler))))
 }
\ No newline at end of file
@@ -0,0 +1,111 @@
+package utils
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// APIGatewayHandler is the signature of the API Gateway proxy handlers
+type APIGatewayHandler func(context.Context, events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error)
+
+// contextKey is unexported so no other package can read or overwrite the values
+type contextKey int
+
+const (
+	loggerKey contextKey = iota
+	invocationKey
+)
+
+// logOutput receives the JSON log records, CloudWatch collects them from stdout
+var logOutput io.Writer = os.Stdout
+
+// invocation collects what the handler learns about the request for the access log
+type invocation struct {
+	userID string
+}
+
+// WithLogging wraps a handler so that every invocation logs JSON records carrying
+// the API Gateway request ID, and ends with an access log entry with the status,
+// latency and user, using the same fields as the backend server. The request ID
+// is returned to the client in the X-Request-ID header. Inside WithTracing the
+// records also carry the trace_id.
+func WithLogging(function string, handler APIGatewayHandler) APIGatewayHandler {
+	logger := slog.New(slog.NewJSONHandler(logOutput, &slog.HandlerOptions{Level: logLevel()})).
+		With("function", function)
+
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		start := time.Now()
+
+		requestID := request.RequestContext.RequestID
+		requestLogger := logger.With("request_id", requestID)
+		if lc, ok := lambdacontext.FromContext(ctx); ok {
+			requestLogger = requestLogger.With("aws_request_id", lc.AwsRequestID)
+		}
+		if sc := trace.SpanContextFromContext(ctx); sc.IsValid() {
+			requestLogger = requestLogger.With("trace_id", sc.TraceID().String())
+		}
+
+		inv := &invocation{}
+		ctx = context.WithValue(ctx, loggerKey, requestLogger)
+		ctx = context.WithValue(ctx, invocationKey, inv)
+
+		response, err := handler(ctx, request)
+
+		if requestID != "" {
+			if response.Headers == nil {
+				response.Headers = map[string]string{}
+			}
+			response.Headers["X-Request-ID"] = requestID
+		}
+
+		attrs := []slog.Attr{
+			slog.String("method", request.HTTPMethod),
+			slog.String("path", request.Path),
+			slog.Int("status", response.StatusCode),
+			slog.Float64("duration_ms", float64(time.Since(start).Microseconds())/1000),
+			slog.String("user_id", inv.userID),
+			slog.String("remote_addr", request.RequestContext.Identity.SourceIP),
+			slog.String("user_agent", request.RequestContext.Identity.UserAgent),
+		}
+		level := slog.LevelInfo
+		if err != nil {
+			level = slog.LevelError
+			attrs = append(attrs, slog.String("error", err.Error()))
+		}
+		requestLogger.LogAttrs(ctx, level, "request", attrs...)
+
+		return response, err
+	}
+}
+
+// Logger returns the invocation logger of ctx, or the default logger outside WithLogging
+func Logger(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerKey).(*slog.Logger); ok {
+		return logger
+	}
+	return slog.Default()
+}
+
+// SetUser records the authenticated user of the invocation for its access log
+func SetUser(ctx context.Context, userID string) {
+	if inv, ok := ctx.Value(invocationKey).(*invocation); ok {
+		inv.userID = userID
+	}
+}
+
+// logLevel reads LOG_LEVEL (debug, info, warn or error), defaulting to info
+func logLevel() slog.Level {
+	var level slog.Level
+	if err := level.UnmarshalText([]byte(strings.TrimSpace(os.Getenv("LOG_LEVEL")))); err != nil {
+		return slog.LevelInfo
+	}
+	return level
+}
@@ -0,0 +1,117 @@
+package utils
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// captureLogs sends the records of loggers created during the test to a buffer
+func captureLogs(t *testing.T) *bytes.Buffer {
+	var logs bytes.Buffer
+	previous := logOutput
+	logOutput = &logs
+	t.Cleanup(func() { logOutput = previous })
+	return &logs
+}
+
+// logRecords decodes the JSON records written to logs
+func logRecords(t *testing.T, logs *bytes.Buffer) []map[string]interface{} {
+	var records []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(logs.String()), "\n") {
+		var record map[string]interface{}
+		require.NoError(t, json.Unmarshal([]byte(line), &record), "log line %q", line)
+		records = append(records, record)
+	}
+	return records
+}
+
+func TestWithLogging(t *testing.T) {
+	logs := captureLogs(t)
+
+	handler := WithLogging("test-function", func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		SetUser(ctx, "user-123")
+		Logger(ctx).Info("Handling request")
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusCreated}, nil
+	})
+
+	request := events.APIGatewayProxyRequest{HTTPMethod: http.MethodPost, Path: "/articles"}
+	request.RequestContext.RequestID = "request-42"
+	response, err := handler(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, "request-42", response.Headers["X-Request-ID"])
+
+	records := logRecords(t, logs)
+	require.Len(t, records, 2)
+
+	// Records logged by the handler carry the request ID
+	assert.Equal(t, "Handling request", records[0]["msg"])
+	assert.Equal(t, "request-42", records[0]["request_id"])
+	assert.Equal(t, "test-function", records[0]["function"])
+
+	// The access log ends the invocation
+	access := records[1]
+	assert.Equal(t, "request", access["msg"])
+	assert.Equal(t, "INFO", access["level"])
+	assert.Equal(t, "POST", access["method"])
+	assert.Equal(t, "/articles", access["path"])
+	assert.Equal(t, float64(http.StatusCreated), access["status"])
+	assert.Equal(t, "user-123", access["user_id"])
+	assert.Equal(t, "request-42", access["request_id"])
+	assert.Contains(t, access, "duration_ms")
+}
+
+func TestWithLogging_Error(t *testing.T) {
+	logs := captureLogs(t)
+
+	handler := WithLogging("test-function", func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{}, errors.New("boom")
+	})
+
+	_, err := handler(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet})
+
+	require.Error(t, err)
+	records := logRecords(t, logs)
+	require.Len(t, records, 1)
+	assert.Equal(t, "ERROR", records[0]["level"])
+	assert.Equal(t, "boom", records[0]["error"])
+}
+
+func TestWithLogging_TraceID(t *testing.T) {
+	logs := captureLogs(t)
+
+	handler := WithLogging("test-function", func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, nil
+	})
+
+	// WithTracing puts the span of the invocation in ctx
+	traceID, err := trace.TraceIDFromHex("4bf92f3577b34da6a3ce929d0e0e4736")
+	require.NoError(t, err)
+	spanID, err := trace.SpanIDFromHex("00f067aa0ba902b7")
+	require.NoError(t, err)
+	ctx := trace.ContextWithSpanContext(context.Background(), trace.NewSpanContext(trace.SpanContextConfig{TraceID: traceID, SpanID: spanID}))
+
+	_, err = handler(ctx, events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet})
+
+	require.NoError(t, err)
+	records := logRecords(t, logs)
+	require.Len(t, records, 1)
+	assert.Equal(t, traceID.String(), records[0]["trace_id"])
+}
+
+func TestLogger_OutsideWithLogging(t *testing.T) {
+	assert.NotNil(t, Logger(context.Background()))
+
+	// SetUser without WithLogging is a no-op
+	SetUser(context.Background(), "user-123")
+}
@@ -0,0 +1,44 @@
+package utils
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"runtime/debug"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// ErrorReporter receives every panic recovered by WithRecovery, e.g. to forward
+// it to an error tracking service
+type ErrorReporter func(ctx context.Context, request events.APIGatewayProxyRequest, err error, stack []byte)
+
+// WithRecovery wraps a handler so that a panic returns a 500 in the RealWorld
+// error format instead of failing the invocation, which API Gateway would turn
+// into a 502 without a body. The panic is logged with its stack and passed to
+// every reporter. Wrap it with WithLogging so the record carries the request ID.
+func WithRecovery(handler APIGatewayHandler, reporters ...ErrorReporter) APIGatewayHandler {
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (response events.APIGatewayProxyResponse, err error) {
+		defer func() {
+			rec := recover()
+			if rec == nil {
+				return
+			}
+
+			panicErr := fmt.Errorf("panic: %v", rec)
+			if recErr, ok := rec.(error); ok {
+				panicErr = fmt.Errorf("panic: %w", recErr)
+			}
+			stack := debug.Stack()
+			Logger(ctx).Error("Recovered from panic", "error", panicErr, "stack", string(stack))
+
+			for _, report := range reporters {
+				report(ctx, request, panicErr, stack)
+			}
+
+			response, err = ErrorResponse(http.StatusInternalServerError, "system", "Internal server error")
+		}()
+
+		return handler(ctx, request)
+	}
+}
@@ -0,0 +1,75 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// assertSystemError checks the 500 response written for a recovered panic
+func assertSystemError(t *testing.T, response events.APIGatewayProxyResponse) {
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+
+	var body map[string]map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &body))
+	assert.Equal(t, []string{"Internal server error"}, body["errors"]["system"])
+}
+
+func TestWithRecovery_Panic(t *testing.T) {
+	boom := errors.New("boom")
+	var reported []error
+	reporter := func(ctx context.Context, request events.APIGatewayProxyRequest, err error, stack []byte) {
+		reported = append(reported, err)
+		assert.NotEmpty(t, stack)
+	}
+
+	handler := WithRecovery(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic(boom)
+	}, reporter, reporter)
+
+	response, err := handler(context.Background(), events.APIGatewayProxyRequest{})
+
+	require.NoError(t, err)
+	assertSystemError(t, response)
+
+	// Every reporter receives the panic, which wraps the panicked error
+	require.Len(t, reported, 2)
+	assert.Equal(t, "panic: boom", reported[0].Error())
+	assert.ErrorIs(t, reported[0], boom)
+}
+
+func TestWithRecovery_PanicValue(t *testing.T) {
+	var reported error
+	handler := WithRecovery(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic("index out of range")
+	}, func(ctx context.Context, request events.APIGatewayProxyRequest, err error, stack []byte) {
+		reported = err
+	})
+
+	response, err := handler(context.Background(), events.APIGatewayProxyRequest{})
+
+	require.NoError(t, err)
+	assertSystemError(t, response)
+	require.Error(t, reported)
+	assert.Equal(t, "panic: index out of range", reported.Error())
+}
+
+func TestWithRecovery_NoPanic(t *testing.T) {
+	handlerErr := errors.New("failed")
+	handler := WithRecovery(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return events.APIGatewayProxyResponse{StatusCode: http.StatusOK}, handlerErr
+	}, func(ctx context.Context, request events.APIGatewayProxyRequest, err error, stack []byte) {
+		t.Error("Expected no report without a panic")
+	})
+
+	response, err := handler(context.Background(), events.APIGatewayProxyRequest{})
+
+	assert.Equal(t, handlerErr, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
@@ -2,57 +2,184 @@ package utils
 
 import (
 	"fmt"
-	"regexp"
+	"os"
 	"strconv"
 	"strings"
+	"unicode"
 )
 
+// SlugStrategy controls how non-ASCII characters are handled when generating slugs
+type SlugStrategy string
+
+const (
+	// SlugStrategyASCII keeps only [a-z0-9] and drops every other character
+	SlugStrategyASCII SlugStrategy = "ascii"
+	// SlugStrategyUnicode keeps Unicode letters and digits, which browsers percent-encode in URLs
+	SlugStrategyUnicode SlugStrategy = "unicode"
+	// SlugStrategyTransliterate converts Hangul, Cyrillic and accented Latin letters to ASCII
+	SlugStrategyTransliterate SlugStrategy = "transliterate"
+)
+
+// SlugOptions configures slug generation
+type SlugOptions struct {
+	Strategy SlugStrategy
+	// MaxLength is the maximum number of characters of a base slug, 0 means unlimited
+	MaxLength int
+	// Reserved slugs are never returned by GenerateUniqueSlug because they would shadow a route
+	Reserved []string
+}
+
+// DefaultSlugOptions returns the options used unless SetSlugOptions is called
+func DefaultSlugOptions() SlugOptions {
+	return SlugOptions{
+		Strategy:  SlugStrategyTransliterate,
+		MaxLength: 100,
+		Reserved:  []string{"feed"},
+	}
+}
+
+var slugOptions = DefaultSlugOptions()
+
+// SetSlugOptions replaces the options used by GenerateSlug and GenerateUniqueSlug
+func SetSlugOptions(opts SlugOptions) {
+	slugOptions = opts
+}
+
+// LoadSlugOptionsFromEnv applies SLUG_STRATEGY and SLUG_MAX_LENGTH on top of the default options
+func LoadSlugOptionsFromEnv() error {
+	opts := DefaultSlugOptions()
+
+	if value := os.Getenv("SLUG_STRATEGY"); value != "" {
+		strategy := SlugStrategy(strings.ToLower(strings.TrimSpace(value)))
+		switch strategy {
+		case SlugStrategyASCII, SlugStrategyUnicode, SlugStrategyTransliterate:
+			opts.Strategy = strategy
+		default:
+			return fmt.Errorf("unknown slug strategy %q", value)
+		}
+	}
+
+	if value := os.Getenv("SLUG_MAX_LENGTH"); value != "" {
+		maxLength, err := strconv.Atoi(value)
+		if err != nil || maxLength < 0 {
+			return fmt.Errorf("invalid slug max length %q", value)
+		}
+		opts.MaxLength = maxLength
+	}
+
+	SetSlugOptions(opts)
+	return nil
+}
+
 // GenerateSlug creates a URL-friendly slug from a title
 func GenerateSlug(title string) string {
-	// Convert to lowercase
-	slug := strings.ToLower(title)
-	
-	// Replace spaces with hyphens
-	slug = strings.ReplaceAll(slug, " ", "-")
-	
-	// Remove non-alphanumeric characters except hyphens
-	reg := regexp.MustCompile(`[^a-z0-9\-]`)
-	slug = reg.ReplaceAllString(slug, "")
-	
-	// Remove multiple consecutive hyphens
-	reg = regexp.MustCompile(`-+`)
-	slug = reg.ReplaceAllString(slug, "-")
-	
-	// Remove leading and trailing hyphens
-	slug = strings.Trim(slug, "-")
-	
-	return slug
+	return generateSlug(title, slugOptions)
 }
 
-// GenerateUniqueSlug generates a unique slug by appending a number if necessary
+// GenerateUniqueSlug creates a unique slug by appending a number suffix if needed.
+// Reserved slugs are treated as taken. The base slug is shortened to make room for
+// the suffix, so the result never exceeds the maximum length.
 func GenerateUniqueSlug(title string, existingSlugs []string) string {
-	baseSlug := GenerateSlug(title)
-	
+	opts := slugOptions
+	baseSlug := generateSlug(title, opts)
+	taken := append(append([]string{}, existingSlugs...), opts.Reserved...)
+
 	// Check if base slug is available
-	if !containsSlug(existingSlugs, baseSlug) {
+	if !containsSlug(taken, baseSlug) {
 		return baseSlug
 	}
-	
-	// Find the highest number suffix
-	maxNum := 0
-	basePattern := baseSlug + "-"
-	
-	for _, slug := range existingSlugs {
-		if strings.HasPrefix(slug, basePattern) {
-			suffix := strings.TrimPrefix(slug, basePattern)
-			if num, err := strconv.Atoi(suffix); err == nil && num > maxNum {
-				maxNum = num
-			}
+
+	// Append number suffix until unique
+	for i := 1; i <= 1000; i++ { // Reasonable limit to prevent infinite loop
+		candidateSlug := appendSuffix(baseSlug, i, opts.MaxLength)
+		if !containsSlug(taken, candidateSlug) {
+			return candidateSlug
+		}
+	}
+
+	return appendSuffix(baseSlug, 999999, opts.MaxLength)
+}
+
+// appendSuffix appends "-n" to a slug, first truncating the slug at a word
+// boundary so that the result fits in maxLength characters
+func appendSuffix(slug string, n, maxLength int) string {
+	suffix := fmt.Sprintf("-%d", n)
+	if maxLength > 0 {
+		// Keep at least one character when the suffix alone nearly fills the limit
+		slug = truncateSlug(slug, max(maxLength-len(suffix), 1))
+	}
+	return slug + suffix
+}
+
+// generateSlug creates a slug from a title using the given options
+func generateSlug(title string, opts SlugOptions) string {
+	var b strings.Builder
+	pendingHyphen := false
+
+	for _, r := range strings.ToLower(title) {
+		var part string
+		switch {
+		case unicode.IsSpace(r) || r == '-':
+			// Spaces and hyphens separate words, consecutive separators collapse
+			pendingHyphen = b.Len() > 0
+			continue
+		case r < unicode.MaxASCII && (unicode.IsLetter(r) || unicode.IsDigit(r)):
+			part = string(r)
+		case r < unicode.MaxASCII:
+			// ASCII punctuation is dropped
+			continue
+		default:
+			part = convertRune(r, opts.Strategy)
+		}
+
+		if part == "" {
+			continue
+		}
+		if pendingHyphen {
+			b.WriteByte('-')
+			pendingHyphen = false
+		}
+		b.WriteString(part)
+	}
+
+	slug := truncateSlug(b.String(), opts.MaxLength)
+
+	// Ensure slug is not empty
+	if slug == "" {
+		slug = "untitled"
+	}
+
+	return slug
+}
+
+// convertRune converts a non-ASCII rune according to the strategy
+func convertRune(r rune, strategy SlugStrategy) string {
+	switch strategy {
+	case SlugStrategyUnicode:
+		if unicode.IsLetter(r) || unicode.IsMark(r) || unicode.IsDigit(r) {
+			return string(r)
 		}
+	case SlugStrategyTransliterate:
+		return transliterate(r)
 	}
-	
-	// Return slug with next available number
-	return fmt.Sprintf("%s-%d", baseSlug, maxNum+1)
+	return ""
+}
+
+// truncateSlug shortens a slug to maxLength characters, cutting at the last hyphen when possible
+func truncateSlug(slug string, maxLength int) string {
+	runes := []rune(slug)
+	if maxLength <= 0 || len(runes) <= maxLength {
+		return slug
+	}
+
+	truncated := string(runes[:maxLength])
+	if runes[maxLength] != '-' {
+		if i := strings.LastIndex(truncated, "-"); i > 0 {
+			truncated = truncated[:i]
+		}
+	}
+
+	return strings.Trim(truncated, "-")
 }
 
 // containsSlug checks if a slice contains a specific slug
@@ -63,4 +190,4 @@ func containsSlug(slugs []string, target string) bool {
 		}
 	}
 	return false
-}
\ No newline at end of file
+}
@@ -0,0 +1,72 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// setSlugOptions changes the slug options for the duration of the test
+func setSlugOptions(t *testing.T, opts SlugOptions) {
+	SetSlugOptions(opts)
+	t.Cleanup(func() { SetSlugOptions(DefaultSlugOptions()) })
+}
+
+func TestGenerateSlug(t *testing.T) {
+	tests := map[string]string{
+		"Hello World":             "hello-world",
+		"This is a Test Article!": "this-is-a-test-article",
+		"Multiple   Spaces":       "multiple-spaces",
+		"Special@#$%Characters":   "specialcharacters",
+		"안녕하세요 세계":                "annyeonghaseyo-segye",
+		"":                        "untitled",
+		"---":                     "untitled",
+	}
+
+	for title, expected := range tests {
+		assert.Equal(t, expected, GenerateSlug(title), "title %q", title)
+	}
+}
+
+func TestGenerateUniqueSlug(t *testing.T) {
+	assert.Equal(t, "unique-title", GenerateUniqueSlug("Unique Title", []string{"hello-world"}))
+
+	// The first free number is used, not the highest plus one
+	assert.Equal(t, "hello-world-1", GenerateUniqueSlug("Hello World", []string{"hello-world", "hello-world-2"}))
+	assert.Equal(t, "hello-world-3", GenerateUniqueSlug("Hello World", []string{"hello-world", "hello-world-1", "hello-world-2"}))
+
+	// Reserved slugs are taken
+	assert.Equal(t, "feed-1", GenerateUniqueSlug("Feed", nil))
+}
+
+func TestGenerateUniqueSlug_MaxLength(t *testing.T) {
+	opts := DefaultSlugOptions()
+	opts.MaxLength = 15
+	setSlugOptions(t, opts)
+
+	assert.Equal(t, "hello-wonderful", GenerateUniqueSlug("Hello Wonderful World", nil))
+
+	// The suffix replaces the last word rather than exceeding the limit
+	slug := GenerateUniqueSlug("Hello Wonderful World", []string{"hello-wonderful"})
+	assert.Equal(t, "hello-1", slug)
+	assert.LessOrEqual(t, len(slug), opts.MaxLength)
+
+	assert.Equal(t, "hello-2", GenerateUniqueSlug("Hello Wonderful World", []string{"hello-wonderful", "hello-1"}))
+}
+
+func TestLoadSlugOptionsFromEnv(t *testing.T) {
+	t.Cleanup(func() { SetSlugOptions(DefaultSlugOptions()) })
+
+	t.Setenv("SLUG_STRATEGY", " Unicode ")
+	t.Setenv("SLUG_MAX_LENGTH", "3")
+	require.NoError(t, LoadSlugOptionsFromEnv())
+	assert.Equal(t, "안녕", GenerateSlug("안녕 세계"))
+
+	t.Setenv("SLUG_STRATEGY", "emoji")
+	assert.Error(t, LoadSlugOptionsFromEnv())
+
+	t.Setenv("SLUG_STRATEGY", "")
+	t.Setenv("SLUG_MAX_LENGTH", "-1")
+	assert.Error(t, LoadSlugOptionsFromEnv())
+}
@@ -2,7 +2,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
 	"strings"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -14,7 +14,7 @@ import (
 
 // HandleGetUser handles get current user requests
 func HandleGetUser(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("GetUser function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight requests
 	if request.HTTPMethod == "OPTIONS" {
@@ -54,28 +54,32 @@ func HandleGetUser(ctx context.Context, request events.APIGatewayProxyRequest) (
 	// Validate JWT token
 	claims, err := auth.ValidateToken(tokenString)
 	if err != nil {
-		log.Printf("Failed to validate token: %v", err)
+		logger.Warn("Failed to validate token", "error", err)
 		return utils.ErrorResponse(401, "token", "Invalid token"), nil
 	}
+	utils.SetUser(ctx, claims.UserID)
 
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository()
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.ErrorResponse(500, "database", "Database initialization error"), nil
 	}
 
 	// Get user from database
 	user, err := repo.GetByID(claims.UserID)
 	if err != nil {
-		log.Printf("Failed to get user by ID: %v", err)
-		return utils.ErrorResponse(404, "user", "User not found"), nil
+		logger.Error("Failed to get user by ID", "error", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.ErrorResponse(404, "user", "User not found"), nil
+		}
+		return utils.ErrorResponse(500, "database", "Database error"), nil
 	}
 
 	// Generate new token (refresh the token)
 	newToken, err := auth.GenerateToken(user.UserID, user.Email, user.Username)
 	if err != nil {
-		log.Printf("Failed to generate token: %v", err)
+		logger.Error("Failed to generate token", "error", err)
 		return utils.ErrorResponse(500, "token", "Failed to generate token"), nil
 	}
 
@@ -84,10 +88,10 @@ func HandleGetUser(ctx context.Context, request events.APIGatewayProxyRequest) (
 		"user": user.ToResponse(newToken),
 	}
 
-	log.Printf("Get user successful: UserID=%s, Email=%s", user.UserID, user.Email)
+	logger.Debug("Get user successful", "user_id", user.UserID)
 	return utils.SuccessResponse(200, responseData), nil
 }
 
 func main() {
-	lambda.Start(HandleGetUser)
+	lambda.Start(utils.WithTracing(utils.WithLogging("get_user", utils.WithRecovery(HandleGetUser))))
 }
\ No newline at end of file
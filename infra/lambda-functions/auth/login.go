@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -15,7 +14,7 @@ import (
 
 // HandleLogin handles user login requests
 func HandleLogin(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Login function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight requests
 	if request.HTTPMethod == "OPTIONS" {
@@ -30,7 +29,7 @@ func HandleLogin(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	// Parse request body
 	var loginReq models.LoginRequest
 	if err := json.Unmarshal([]byte(request.Body), &loginReq); err != nil {
-		log.Printf("Failed to parse JSON: %v", err)
+		logger.Info("Failed to parse JSON", "error", err)
 		return utils.ErrorResponse(400, "body", "Invalid JSON"), nil
 	}
 
@@ -51,27 +50,27 @@ func HandleLogin(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository()
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.ErrorResponse(500, "database", "Database initialization error"), nil
 	}
 
 	// Get user by email
 	user, err := repo.GetByEmail(email)
 	if err != nil {
-		log.Printf("Failed to get user by email: %v", err)
+		logger.Error("Failed to get user by email", "error", err)
 		return utils.ErrorResponse(401, "email", "Invalid email or password"), nil
 	}
 
 	// Check password
 	if !auth.CheckPasswordHash(password, user.PasswordHash) {
-		log.Printf("Invalid password for user: %s", email)
+		logger.Info("Invalid password", "email", email)
 		return utils.ErrorResponse(401, "password", "Invalid email or password"), nil
 	}
 
 	// Generate JWT token
 	token, err := auth.GenerateToken(user.UserID, user.Email, user.Username)
 	if err != nil {
-		log.Printf("Failed to generate token: %v", err)
+		logger.Error("Failed to generate token", "error", err)
 		return utils.ErrorResponse(500, "token", "Failed to generate token"), nil
 	}
 
@@ -80,10 +79,11 @@ func HandleLogin(ctx context.Context, request events.APIGatewayProxyRequest) (ev
 		"user": user.ToResponse(token),
 	}
 
-	log.Printf("User login successful: UserID=%s, Email=%s", user.UserID, user.Email)
+	utils.SetUser(ctx, user.UserID)
+	logger.Info("User login successful", "user_id", user.UserID)
 	return utils.SuccessResponse(200, responseData), nil
 }
 
 func main() {
-	lambda.Start(HandleLogin)
+	lambda.Start(utils.WithTracing(utils.WithLogging("login", utils.WithRecovery(HandleLogin))))
 }
\ No newline at end of file
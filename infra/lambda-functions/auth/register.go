@@ -3,7 +3,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
 
 	"github.com/aws/aws-lambda-go/events"
 	"github.com/aws/aws-lambda-go/lambda"
@@ -15,7 +14,7 @@ import (
 
 // HandleRegister handles user registration requests
 func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Register function invoked: Method=%s, Path=%s", request.HTTPMethod, request.Path)
+	logger := utils.Logger(ctx)
 
 	// Handle CORS preflight requests
 	if request.HTTPMethod == "OPTIONS" {
@@ -30,7 +29,7 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 	// Parse request body
 	var registerReq models.RegisterRequest
 	if err := json.Unmarshal([]byte(request.Body), &registerReq); err != nil {
-		log.Printf("Failed to parse JSON: %v", err)
+		logger.Info("Failed to parse JSON", "error", err)
 		return utils.ErrorResponse(400, "body", "Invalid JSON"), nil
 	}
 
@@ -69,14 +68,14 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository()
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.ErrorResponse(500, "database", "Database initialization error"), nil
 	}
 
 	// Check if email already exists
 	emailExists, err := repo.EmailExists(email)
 	if err != nil {
-		log.Printf("Failed to check email existence: %v", err)
+		logger.Error("Failed to check email existence", "error", err)
 		return utils.ErrorResponse(500, "database", "Database error"), nil
 	}
 
@@ -87,7 +86,7 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 	// Check if username already exists
 	usernameExists, err := repo.UsernameExists(username)
 	if err != nil {
-		log.Printf("Failed to check username existence: %v", err)
+		logger.Error("Failed to check username existence", "error", err)
 		return utils.ErrorResponse(500, "database", "Database error"), nil
 	}
 
@@ -98,7 +97,7 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 	// Hash password
 	hashedPassword, err := auth.HashPassword(password)
 	if err != nil {
-		log.Printf("Failed to hash password: %v", err)
+		logger.Error("Failed to hash password", "error", err)
 		return utils.ErrorResponse(500, "password", "Failed to process password"), nil
 	}
 
@@ -112,14 +111,14 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 	}
 
 	if err := repo.Create(user); err != nil {
-		log.Printf("Failed to create user: %v", err)
+		logger.Error("Failed to create user", "error", err)
 		return utils.ErrorResponse(500, "database", "Failed to create user"), nil
 	}
 
 	// Generate JWT token
 	token, err := auth.GenerateToken(user.UserID, user.Email, user.Username)
 	if err != nil {
-		log.Printf("Failed to generate token: %v", err)
+		logger.Error("Failed to generate token", "error", err)
 		return utils.ErrorResponse(500, "token", "Failed to generate token"), nil
 	}
 
@@ -128,10 +127,11 @@ func HandleRegister(ctx context.Context, request events.APIGatewayProxyRequest)
 		"user": user.ToResponse(token),
 	}
 
-	log.Printf("User registration successful: UserID=%s, Email=%s, Username=%s", user.UserID, user.Email, user.Username)
+	utils.SetUser(ctx, user.UserID)
+	logger.Info("User registration successful", "user_id", user.UserID)
 	return utils.SuccessResponse(201, responseData), nil
 }
 
 func main() {
-	lambda.Start(HandleRegister)
+	lambda.Start(utils.WithTracing(utils.WithLogging("register", utils.WithRecovery(HandleRegister))))
 }
\ No newline at end of file
@@ -6,6 +6,7 @@ import (
 	"time"
 
 	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
 	"github.com/aws/aws-sdk-go/aws/session"
 	"github.com/aws/aws-sdk-go/service/dynamodb"
 	"github.com/aws/aws-sdk-go/service/dynamodb/dynamodbattribute"
@@ -65,6 +66,10 @@ func (r *DynamoDBRepository) Create(user *models.User) error {
 	})
 
 	if err != nil {
+		// The condition fails when a user with the same key already exists
+		if aerr, ok := err.(awserr.Error); ok && aerr.Code() == dynamodb.ErrCodeConditionalCheckFailedException {
+			return &Error{Kind: ErrConflict, Resource: "user"}
+		}
 		return fmt.Errorf("failed to create user in DynamoDB: %w", err)
 	}
 
@@ -87,7 +92,7 @@ func (r *DynamoDBRepository) GetByEmail(email string) (*models.User, error) {
 	}
 
 	if len(result.Items) == 0 {
-		return nil, fmt.Errorf("user not found")
+		return nil, &Error{Kind: ErrNotFound, Resource: "user"}
 	}
 
 	var user models.User
@@ -115,7 +120,7 @@ func (r *DynamoDBRepository) GetByUsername(username string) (*models.User, error
 	}
 
 	if len(result.Items) == 0 {
-		return nil, fmt.Errorf("user not found")
+		return nil, &Error{Kind: ErrNotFound, Resource: "user"}
 	}
 
 	var user models.User
@@ -143,7 +148,7 @@ func (r *DynamoDBRepository) GetByID(userID string) (*models.User, error) {
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("user not found")
+		return nil, &Error{Kind: ErrNotFound, Resource: "user"}
 	}
 
 	var user models.User
@@ -5,7 +5,6 @@ package main
 import (
 	"context"
 	"encoding/json"
-	"log"
 	"net/http"
 	"os"
 	"strings"
@@ -20,12 +19,12 @@ import (
 )
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(utils.WithTracing(utils.WithLogging("create_comment", utils.WithRecovery(HandleRequest))))
 }
 
 // HandleRequest handles the Lambda request for creating a comment
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Received request: %+v", request)
+	logger := utils.Logger(ctx)
 
 	// Extract article slug from path parameters
 	articleSlug := request.PathParameters["slug"]
@@ -36,7 +35,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	// Get JWT secret from environment
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
-		log.Printf("JWT_SECRET environment variable not set")
+		logger.Error("JWT_SECRET environment variable not set")
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
@@ -44,14 +43,15 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	authHeader := request.Headers["Authorization"]
 	username, err := auth.ExtractUsernameFromAuth(authHeader, jwtSecret)
 	if err != nil {
-		log.Printf("Failed to extract username from auth: %v", err)
+		logger.Warn("Failed to extract username from auth", "error", err)
 		return utils.NewErrorResponse(http.StatusUnauthorized, "authorization", "Invalid or missing token")
 	}
+	utils.SetUser(ctx, username)
 
 	// Parse request body
 	var commentReq models.CreateCommentRequest
 	if err := json.Unmarshal([]byte(request.Body), &commentReq); err != nil {
-		log.Printf("Failed to parse request body: %v", err)
+		logger.Info("Failed to parse request body", "error", err)
 		return utils.NewErrorResponse(http.StatusBadRequest, "body", "Invalid request format")
 	}
 
@@ -64,14 +64,14 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	// Get table name from environment
 	tableName := os.Getenv("COMMENTS_TABLE_NAME")
 	if tableName == "" {
-		log.Printf("COMMENTS_TABLE_NAME environment variable not set")
+		logger.Error("COMMENTS_TABLE_NAME environment variable not set")
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository(tableName)
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
@@ -83,14 +83,14 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 
 	// Save comment to database
 	if err := repo.CreateComment(comment); err != nil {
-		log.Printf("Failed to create comment: %v", err)
+		logger.Error("Failed to create comment", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Failed to create comment")
 	}
 
 	// Get author information
 	author, err := repo.GetUserByUsername(username)
 	if err != nil {
-		log.Printf("Failed to get author info: %v", err)
+		logger.Error("Failed to get author info", "error", err)
 		// Use basic author info if lookup fails
 		author = &models.Author{
 			Username:  username,
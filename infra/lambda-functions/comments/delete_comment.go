@@ -4,7 +4,7 @@ package main
 
 import (
 	"context"
-	"log"
+	"errors"
 	"net/http"
 	"os"
 
@@ -16,12 +16,12 @@ import (
 )
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(utils.WithTracing(utils.WithLogging("delete_comment", utils.WithRecovery(HandleRequest))))
 }
 
 // HandleRequest handles the Lambda request for deleting a comment
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Received request: %+v", request)
+	logger := utils.Logger(ctx)
 
 	// Extract article slug and comment ID from path parameters
 	articleSlug := request.PathParameters["slug"]
@@ -37,7 +37,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	// Get JWT secret from environment
 	jwtSecret := os.Getenv("JWT_SECRET")
 	if jwtSecret == "" {
-		log.Printf("JWT_SECRET environment variable not set")
+		logger.Error("JWT_SECRET environment variable not set")
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
@@ -45,40 +45,44 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	authHeader := request.Headers["Authorization"]
 	username, err := auth.ExtractUsernameFromAuth(authHeader, jwtSecret)
 	if err != nil {
-		log.Printf("Failed to extract username from auth: %v", err)
+		logger.Warn("Failed to extract username from auth", "error", err)
 		return utils.NewErrorResponse(http.StatusUnauthorized, "authorization", "Invalid or missing token")
 	}
+	utils.SetUser(ctx, username)
 
 	// Get table name from environment
 	tableName := os.Getenv("COMMENTS_TABLE_NAME")
 	if tableName == "" {
-		log.Printf("COMMENTS_TABLE_NAME environment variable not set")
+		logger.Error("COMMENTS_TABLE_NAME environment variable not set")
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository(tableName)
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// Get the comment to verify ownership
 	comment, err := repo.GetComment(articleSlug, commentID)
 	if err != nil {
-		log.Printf("Failed to get comment: %v", err)
-		return utils.NewErrorResponse(http.StatusNotFound, "comment", "Comment not found")
+		logger.Error("Failed to get comment", "error", err)
+		if errors.Is(err, repository.ErrNotFound) {
+			return utils.NewErrorResponse(http.StatusNotFound, "comment", "Comment not found")
+		}
+		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// Check if the user is the author of the comment
 	if comment.AuthorUsername != username {
-		log.Printf("User %s tried to delete comment by %s", username, comment.AuthorUsername)
+		logger.Warn("User tried to delete another user's comment", "username", username, "author", comment.AuthorUsername)
 		return utils.NewErrorResponse(http.StatusForbidden, "authorization", "You can only delete your own comments")
 	}
 
 	// Delete the comment
 	if err := repo.DeleteComment(articleSlug, commentID); err != nil {
-		log.Printf("Failed to delete comment: %v", err)
+		logger.Error("Failed to delete comment", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Failed to delete comment")
 	}
 
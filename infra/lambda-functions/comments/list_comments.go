@@ -4,7 +4,6 @@ package main
 
 import (
 	"context"
-	"log"
 	"net/http"
 	"os"
 
@@ -16,12 +15,12 @@ import (
 )
 
 func main() {
-	lambda.Start(HandleRequest)
+	lambda.Start(utils.WithTracing(utils.WithLogging("list_comments", utils.WithRecovery(HandleRequest))))
 }
 
 // HandleRequest handles the Lambda request for listing comments
 func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
-	log.Printf("Received request: %+v", request)
+	logger := utils.Logger(ctx)
 
 	// Extract article slug from path parameters
 	articleSlug := request.PathParameters["slug"]
@@ -32,21 +31,21 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	// Get table name from environment
 	tableName := os.Getenv("COMMENTS_TABLE_NAME")
 	if tableName == "" {
-		log.Printf("COMMENTS_TABLE_NAME environment variable not set")
+		logger.Error("COMMENTS_TABLE_NAME environment variable not set")
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// Initialize repository
 	repo, err := repository.NewDynamoDBRepository(tableName)
 	if err != nil {
-		log.Printf("Failed to initialize repository: %v", err)
+		logger.Error("Failed to initialize repository", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Internal server error")
 	}
 
 	// List comments for the article
 	comments, err := repo.ListCommentsByArticle(articleSlug)
 	if err != nil {
-		log.Printf("Failed to list comments: %v", err)
+		logger.Error("Failed to list comments", "error", err)
 		return utils.NewErrorResponse(http.StatusInternalServerError, "server", "Failed to retrieve comments")
 	}
 
@@ -54,7 +53,7 @@ func HandleRequest(ctx context.Context, request events.APIGatewayProxyRequest) (
 	for i := range comments {
 		author, err := repo.GetUserByUsername(comments[i].AuthorUsername)
 		if err != nil {
-			log.Printf("Failed to get author info for %s: %v", comments[i].AuthorUsername, err)
+			logger.Warn("Failed to get author info", "author", comments[i].AuthorUsername, "error", err)
 			// Use basic author info if lookup fails
 			author = &models.Author{
 				Username:  comments[i].AuthorUsername,
@@ -107,7 +107,7 @@ func (r *DynamoDBRepository) GetComment(articleSlug, commentID string) (*models.
 	}
 
 	if result.Item == nil {
-		return nil, fmt.Errorf("comment not found")
+		return nil, &Error{Kind: ErrNotFound, Resource: "comment"}
 	}
 
 	var comment models.Comment
@@ -0,0 +1,23 @@
+package repository
+
+import "errors"
+
+// ErrNotFound is returned for a comment that does not exist, compare it with errors.Is
+var ErrNotFound = errors.New("not found")
+
+// Error is a repository error about a specific resource such as a "comment".
+// errors.Is matches it against its Kind.
+type Error struct {
+	Kind     error
+	Resource string
+}
+
+// Error returns a message such as "comment not found"
+func (e *Error) Error() string {
+	return e.Resource + " " + e.Kind.Error()
+}
+
+// Unwrap returns the sentinel error
+func (e *Error) Unwrap() error {
+	return e.Kind
+}
@@ -0,0 +1,48 @@
+package utils
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithRecovery_Panic(t *testing.T) {
+	var reported error
+	reporter := func(ctx context.Context, request events.APIGatewayProxyRequest, err error, stack []byte) {
+		reported = err
+		assert.NotEmpty(t, stack)
+	}
+
+	handler := WithRecovery(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		panic(errors.New("boom"))
+	}, reporter)
+
+	response, err := handler(context.Background(), events.APIGatewayProxyRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusInternalServerError, response.StatusCode)
+
+	var body map[string]map[string][]string
+	require.NoError(t, json.Unmarshal([]byte(response.Body), &body))
+	assert.Len(t, body["errors"]["system"], 1)
+
+	require.Error(t, reported)
+	assert.Equal(t, "panic: boom", reported.Error())
+}
+
+func TestWithRecovery_NoPanic(t *testing.T) {
+	handler := WithRecovery(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		return NewResponse(http.StatusOK, nil)
+	})
+
+	response, err := handler(context.Background(), events.APIGatewayProxyRequest{})
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+}
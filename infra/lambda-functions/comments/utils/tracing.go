@@ -0,0 +1,109 @@
+package utils
+
+import (
+	"context"
+	"log/slog"
+	"net/http"
+	"os"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/aws/aws-lambda-go/lambdacontext"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.opentelemetry.io/otel/trace/noop"
+)
+
+// WithTracing wraps a handler in a server span that continues the W3C trace of
+// the caller: a traceparent header sent through API Gateway becomes the parent
+// of the span, which is passed on to the handler in ctx. Spans are exported over
+// OTLP/HTTP when OTEL_TRACES_EXPORTER is otlp, configured by the standard
+// OTEL_EXPORTER_OTLP_* variables, and dropped otherwise. Wrap WithLogging with
+// it so that log records carry the trace ID.
+func WithTracing(handler APIGatewayHandler) APIGatewayHandler {
+	tracer := newTracerProvider().Tracer("github.com/vibe-coding-paradigm/realworld-serverless/utils")
+	propagator := propagation.TraceContext{}
+
+	return func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		ctx = propagator.Extract(ctx, propagation.HeaderCarrier(requestHeader(request)))
+
+		// Resource is the route template such as /articles/{slug}
+		ctx, span := tracer.Start(ctx, request.HTTPMethod+" "+request.Resource,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				semconv.HTTPRequestMethodKey.String(request.HTTPMethod),
+				semconv.HTTPRoute(request.Resource),
+				semconv.URLPath(request.Path),
+			),
+		)
+		if lc, ok := lambdacontext.FromContext(ctx); ok {
+			span.SetAttributes(semconv.FaaSInvocationID(lc.AwsRequestID))
+		}
+		// The span is exported as it ends, before the execution environment is frozen
+		defer span.End()
+
+		response, err := handler(ctx, request)
+
+		span.SetAttributes(semconv.HTTPResponseStatusCode(response.StatusCode))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+		} else if response.StatusCode >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(response.StatusCode))
+		}
+
+		return response, err
+	}
+}
+
+// newTracerProvider returns an OTLP exporting provider when OTEL_TRACES_EXPORTER
+// is otlp and a no-op provider otherwise. The no-op provider still continues an
+// incoming trace, so log records carry the caller's trace ID.
+func newTracerProvider() trace.TracerProvider {
+	if os.Getenv("OTEL_TRACES_EXPORTER") != "otlp" {
+		return noop.NewTracerProvider()
+	}
+
+	ctx := context.Background()
+	exporter, err := otlptracehttp.New(ctx)
+	if err != nil {
+		slog.Error("Failed to create OTLP exporter, tracing is disabled", "error", err)
+		return noop.NewTracerProvider()
+	}
+
+	// OTEL_SERVICE_NAME, when set, overrides the function name
+	res, err := resource.New(ctx,
+		resource.WithAttributes(semconv.ServiceName(lambdacontext.FunctionName)),
+		resource.WithFromEnv(),
+		resource.WithTelemetrySDK(),
+	)
+	if err != nil {
+		slog.Warn("Failed to detect tracing resource", "error", err)
+	}
+
+	// A synchronous exporter because a batch would wait for the next invocation
+	return sdktrace.NewTracerProvider(
+		sdktrace.WithSyncer(exporter),
+		sdktrace.WithResource(res),
+	)
+}
+
+// requestHeader collects the request headers with canonical names, API Gateway
+// passes them in the case the client used
+func requestHeader(request events.APIGatewayProxyRequest) http.Header {
+	header := http.Header{}
+	for name, value := range request.Headers {
+		header.Set(name, value)
+	}
+	for name, values := range request.MultiValueHeaders {
+		header.Del(name)
+		for _, value := range values {
+			header.Add(name, value)
+		}
+	}
+	return header
+}
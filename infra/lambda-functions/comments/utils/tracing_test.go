@@ -0,0 +1,73 @@
+package utils
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/aws/aws-lambda-go/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel/trace"
+)
+
+func TestWithTracing_ContinuesTraceparent(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+
+	var spanContext trace.SpanContext
+	handler := WithTracing(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		spanContext = trace.SpanContextFromContext(ctx)
+		return NewResponse(http.StatusOK, nil)
+	})
+
+	// API Gateway passes headers in the case the client sent them
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		Resource:   "/articles/{slug}/comments",
+		Headers: map[string]string{
+			"Traceparent": "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01",
+		},
+	}
+	response, err := handler(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, http.StatusOK, response.StatusCode)
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", spanContext.TraceID().String())
+}
+
+func TestWithTracing_NoTraceparent(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+
+	var spanContext trace.SpanContext
+	handler := WithTracing(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		spanContext = trace.SpanContextFromContext(ctx)
+		return NewResponse(http.StatusOK, nil)
+	})
+
+	_, err := handler(context.Background(), events.APIGatewayProxyRequest{HTTPMethod: http.MethodGet})
+
+	require.NoError(t, err)
+	assert.False(t, spanContext.IsValid())
+}
+
+func TestWithTracing_MultiValueTraceparent(t *testing.T) {
+	t.Setenv("OTEL_TRACES_EXPORTER", "")
+
+	var spanContext trace.SpanContext
+	handler := WithTracing(func(ctx context.Context, request events.APIGatewayProxyRequest) (events.APIGatewayProxyResponse, error) {
+		spanContext = trace.SpanContextFromContext(ctx)
+		return NewResponse(http.StatusOK, nil)
+	})
+
+	// Multi-value headers take precedence over the single-value ones
+	request := events.APIGatewayProxyRequest{
+		HTTPMethod: http.MethodGet,
+		MultiValueHeaders: map[string][]string{
+			"traceparent": {"00-0af7651916cd43dd8448eb211c80319c-b7ad6b7169203331-01"},
+		},
+	}
+	_, err := handler(context.Background(), request)
+
+	require.NoError(t, err)
+	assert.Equal(t, "0af7651916cd43dd8448eb211c80319c", spanContext.TraceID().String())
+}
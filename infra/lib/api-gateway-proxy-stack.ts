@@ -47,7 +47,9 @@ export class ApiGatewayProxyStack extends Construct {
           'Authorization',
           'X-Amz-Date',
           'X-Api-Key',
-          'X-Amz-Security-Token'
+          'X-Amz-Security-Token',
+          'traceparent',
+          'tracestate'
         ],
         allowCredentials: false,
         maxAge: cdk.Duration.hours(1)
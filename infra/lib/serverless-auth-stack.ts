@@ -180,6 +180,8 @@ export class ServerlessAuthStack extends cdk.NestedStack {
             'X-Amz-Date',
             'X-Api-Key',
             'X-Amz-Security-Token',
+            'traceparent',
+            'tracestate',
           ],
           allowCredentials: false,
         },